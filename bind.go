@@ -0,0 +1,145 @@
+package router
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+)
+
+// BindQuery decodes the query parameters of the request into the struct pointed
+// to by dst. Fields are matched by their `query` tag, or by their name when the
+// tag is absent; fields tagged `query:"-"` are skipped. Documented query
+// parameters with a Schema.Default are already applied when the client omits
+// them.
+func BindQuery(req *http.Request, dst any) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return errors.New("router: BindQuery requires a pointer to a struct")
+	}
+
+	return bindValues(req.URL.Query(), v.Elem(), "query")
+}
+
+func bindValues(values url.Values, v reflect.Value, tagName string) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name := field.Tag.Get(tagName)
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		raw, ok := values[name]
+		if !ok || len(raw) == 0 {
+			continue
+		}
+
+		fv := v.Field(i)
+		if fv.Kind() == reflect.Slice {
+			slice := reflect.MakeSlice(fv.Type(), len(raw), len(raw))
+			for j, s := range raw {
+				if err := setValue(slice.Index(j), s); err != nil {
+					return fmt.Errorf("router: %s %q: %w", tagName, name, err)
+				}
+			}
+			fv.Set(slice)
+			continue
+		}
+
+		if err := setValue(fv, raw[0]); err != nil {
+			return fmt.Errorf("router: %s %q: %w", tagName, name, err)
+		}
+	}
+
+	return nil
+}
+
+func setValue(v reflect.Value, s string) error {
+	if v.Kind() == reflect.Ptr {
+		ptr := reflect.New(v.Type().Elem())
+		if err := setValue(ptr.Elem(), s); err != nil {
+			return err
+		}
+		v.Set(ptr)
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(n)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+
+	return nil
+}
+
+// withQueryDefaults fills in the documented default of query parameters the
+// client omitted, so the handler behaves as the docs describe.
+func withQueryDefaults(handler http.HandlerFunc, params []Parameter) http.Handler {
+	defaults := url.Values{}
+	for _, p := range params {
+		if p.In == "query" && p.Schema != nil && p.Schema.Default != nil {
+			defaults.Set(p.Name, fmt.Sprint(p.Schema.Default))
+		}
+	}
+
+	if len(defaults) == 0 {
+		return handler
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		query := req.URL.Query()
+
+		var missing bool
+		for name, value := range defaults {
+			if !query.Has(name) {
+				query[name] = value
+				missing = true
+			}
+		}
+
+		if missing {
+			req = req.WithContext(req.Context())
+			u := *req.URL
+			u.RawQuery = query.Encode()
+			req.URL = &u
+		}
+
+		handler(w, req)
+	})
+}
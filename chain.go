@@ -0,0 +1,62 @@
+package router
+
+import (
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+// MiddlewareFor returns the names of the router middlewares wrapping the route
+// registered for the method and full pattern, in execution order, or nil when
+// no such route exists. Middlewares are named after the function that declared
+// them, e.g. middleware.Timer or middleware.CORS for the closure it returns.
+// It is meant as a debugging aid for middleware ordering.
+func (r *Router) MiddlewareFor(method, pattern string) []string {
+	rootRouter := r.rootParent()
+	rootRouter.mu.RLock()
+	defer rootRouter.mu.RUnlock()
+
+	for i := len(rootRouter.routes) - 1; i >= 0; i-- {
+		if rt := rootRouter.routes[i]; rt.method == method && rt.pattern == pattern {
+			return append([]string{}, rt.middlewares...)
+		}
+	}
+
+	return nil
+}
+
+func middlewareNames(middlewares []Middleware) []string {
+	names := make([]string, len(middlewares))
+	for i, mw := range middlewares {
+		names[i] = middlewareName(mw)
+	}
+	return names
+}
+
+// middlewareName returns the package qualified name of the function declaring
+// the middleware, without the suffixes of closures.
+func middlewareName(mw Middleware) string {
+	fn := runtime.FuncForPC(reflect.ValueOf(mw).Pointer())
+	if fn == nil {
+		return "unknown"
+	}
+
+	name := fn.Name()
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+
+	// closures are named func1, func2, ... or just numbered when nested
+	for {
+		i := strings.LastIndex(name, ".")
+		if i < 0 {
+			break
+		}
+		if rest := strings.TrimRight(name[i+1:], "0123456789"); rest != "" && rest != "func" {
+			break
+		}
+		name = name[:i]
+	}
+
+	return strings.TrimSuffix(name, "-fm")
+}
@@ -0,0 +1,167 @@
+package router
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// componentNamePattern is the pattern component names must match in OpenAPI.
+var componentNamePattern = regexp.MustCompile(`^[a-zA-Z0-9._-]+$`)
+
+// Check validates the registered routes and their documentation without
+// serving requests, e.g. from a test in CI. It reports malformed patterns,
+// duplicate routes and operationIds, invalid component names, references to
+// undefined component schemas and the structural violations found by
+// OpenAPI.Validate, such as operations without responses. All problems are
+// returned joined in a single error, or nil when there are none.
+func (r *Router) Check() error {
+	var errs []error
+
+	rootRouter := r.rootParent()
+	rootRouter.mu.RLock()
+	routes := slices.Clone(rootRouter.routes)
+	rootRouter.mu.RUnlock()
+
+	seen := make(map[string]bool)
+	for _, rt := range routes {
+		route := strings.TrimSpace(rt.method + " " + rt.host + rt.pattern)
+		if err := checkPattern(rt.pattern); err != nil {
+			errs = append(errs, fmt.Errorf("router: route %s: %w", route, err))
+		}
+		if seen[route] {
+			errs = append(errs, fmt.Errorf("router: duplicate route %s", route))
+		}
+		seen[route] = true
+	}
+
+	doc := r.OpenAPI()
+
+	paths := make([]string, 0, len(doc.Paths))
+	for path := range doc.Paths {
+		paths = append(paths, path)
+	}
+	slices.Sort(paths)
+
+	operationIDs := make(map[string]string)
+	for _, path := range paths {
+		item := doc.Paths[path]
+		for _, method := range item.Methods() {
+			op := item.GetMethod(method)
+			route := method + " " + path
+
+			if op.OperationID != "" {
+				if other, ok := operationIDs[op.OperationID]; ok {
+					errs = append(errs, fmt.Errorf("router: duplicate operationId %q on %s and %s", op.OperationID, other, route))
+				} else {
+					operationIDs[op.OperationID] = route
+				}
+			}
+
+			mapOperationSchemas(op, func(s *Schema) *Schema {
+				walkRefs(s, func(ref string) {
+					if !refResolves(ref, doc.Components.Schemas) {
+						errs = append(errs, fmt.Errorf("router: %s references undefined schema %s", route, ref))
+					}
+				})
+				return s
+			})
+		}
+	}
+
+	names := make([]string, 0, len(doc.Components.Schemas))
+	for name := range doc.Components.Schemas {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		if !componentNamePattern.MatchString(name) {
+			errs = append(errs, fmt.Errorf("router: schema name %q must match %s", name, componentNamePattern))
+		}
+
+		schema := doc.Components.Schemas[name]
+		walkRefs(&schema, func(ref string) {
+			if !refResolves(ref, doc.Components.Schemas) {
+				errs = append(errs, fmt.Errorf("router: schema %s references undefined schema %s", name, ref))
+			}
+		})
+	}
+
+	if err := doc.Validate(); err != nil {
+		var verrs ValidationErrors
+		if errors.As(err, &verrs) {
+			for _, verr := range verrs {
+				errs = append(errs, fmt.Errorf("router: %w", verr))
+			}
+		} else {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// checkPattern reports malformed wildcards in a route pattern.
+func checkPattern(pattern string) error {
+	if !strings.HasPrefix(pattern, "/") {
+		return errors.New("pattern must start with /")
+	}
+
+	segments := strings.Split(pattern, "/")
+	for i, segment := range segments {
+		open, closed := strings.Count(segment, "{"), strings.Count(segment, "}")
+		if open == 0 && closed == 0 {
+			continue
+		}
+		if open != 1 || closed != 1 || segment[0] != '{' || segment[len(segment)-1] != '}' {
+			return fmt.Errorf("wildcard %q must be a full path segment", segment)
+		}
+
+		name := segment[1 : len(segment)-1]
+		if name == "$" {
+			if i != len(segments)-1 {
+				return errors.New("{$} must be at the end of the pattern")
+			}
+			continue
+		}
+
+		name, multi := strings.CutSuffix(name, "...")
+		if multi && i != len(segments)-1 {
+			return fmt.Errorf("wildcard %q must be at the end of the pattern", segment)
+		}
+		if name == "" {
+			return fmt.Errorf("wildcard %q has no name", segment)
+		}
+	}
+
+	return nil
+}
+
+// walkRefs calls fn for every reference in the schema and its nested schemas.
+func walkRefs(s *Schema, fn func(ref string)) {
+	if s == nil {
+		return
+	}
+
+	if s.Ref != "" {
+		fn(s.Ref)
+	}
+
+	mapSchemaChildren(*s, func(child *Schema) *Schema {
+		walkRefs(child, fn)
+		return child
+	})
+}
+
+func refResolves(ref string, schemas map[string]Schema) bool {
+	name, ok := strings.CutPrefix(ref, "#/components/schemas/")
+	if !ok {
+		return true // only local component references can be checked
+	}
+
+	_, exists := schemas[name]
+	return exists
+}
@@ -0,0 +1,60 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/donseba/go-router/middleware"
+)
+
+// UseCORS applies the CORS policy to the routes registered on the router and
+// its groups from now on, including their preflight requests: every route gets
+// an OPTIONS handler wrapped in the router's middlewares, so the preflight is
+// answered by this policy rather than by the router's automatic OPTIONS
+// handling, even without UseOpenapiDocs. Groups can thus use different
+// policies:
+//
+//	r.Group("/public", func(public *router.Router) {
+//		public.UseCORS(middleware.CORSOptions{AllowedOrigins: []string{"*"}})
+//	})
+//
+// The preflight methods default to those of the requested path, see
+// AllowedMethods.
+func (r *Router) UseCORS(options middleware.CORSOptions) {
+	if options.AllowedMethodsFunc == nil {
+		options.AllowedMethodsFunc = r.AllowedMethods
+	}
+
+	r.Use(middleware.CORS(options))
+	r.corsPreflight = true
+}
+
+// registerPreflight registers the OPTIONS handler of a route of a UseCORS
+// router, once per pattern.
+func (r *Router) registerPreflight(pattern string) {
+	rootRouter := r.rootParent()
+	if !rootRouter.markPreflight(r.host + pattern) {
+		return
+	}
+
+	preflight := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		rootRouter.writeOptions(w, addIfMissing(rootRouter.AllowedMethods(req), http.MethodOptions, true))
+	})
+	r.mountRoute(http.MethodOptions, pattern, r.wrap(preflight), middlewareNames(r.middlewares))
+}
+
+// markPreflight records the host and pattern of a preflight handler, which the
+// automatic OPTIONS handling then skips. It reports false when it was already
+// recorded.
+func (r *Router) markPreflight(key string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.preflights[key] {
+		return false
+	}
+	if r.preflights == nil {
+		r.preflights = make(map[string]bool)
+	}
+	r.preflights[key] = true
+	return true
+}
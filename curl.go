@@ -0,0 +1,107 @@
+package router
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// CurlExample returns a curl command calling the documented operation. Path
+// parameters are filled with <name> placeholders, or their default, required
+// query and header parameters are included, and a JSON body is generated from
+// the request body schema. The URL is prefixed with the first server endpoint.
+func (r *Router) CurlExample(method, pattern string) (string, error) {
+	rootRouter := r.rootParent()
+	rootRouter.mu.RLock()
+	defer rootRouter.mu.RUnlock()
+
+	doc := rootRouter.openapi
+	pattern = strings.ReplaceAll(pattern, "{$}", "")
+
+	op := doc.Paths[pattern].GetMethod(method)
+	if op == nil {
+		return "", fmt.Errorf("router: no documented operation for %s %s", method, pattern)
+	}
+
+	var (
+		path    = pattern
+		query   = url.Values{}
+		headers []string
+	)
+
+	for _, p := range op.Parameters {
+		value := "<" + p.Name + ">"
+		if p.Schema != nil && p.Schema.Default != nil {
+			value = fmt.Sprint(p.Schema.Default)
+		}
+
+		switch p.In {
+		case "path":
+			path = strings.ReplaceAll(path, "{"+p.Name+"}", value)
+			path = strings.ReplaceAll(path, "{"+p.Name+"...}", value)
+		case "query":
+			if p.Required {
+				query.Set(p.Name, value)
+			}
+		case "header":
+			if p.Required {
+				headers = append(headers, p.Name+": "+value)
+			}
+		}
+	}
+
+	var server string
+	if len(doc.Servers) > 0 {
+		server = strings.TrimSuffix(doc.Servers[0].URL, "/")
+	}
+
+	target := server + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "curl -X %s %s", method, shellQuote(target))
+
+	sort.Strings(headers)
+	for _, h := range headers {
+		fmt.Fprintf(&b, " \\\n  -H %s", shellQuote(h))
+	}
+
+	if op.RequestBody != nil {
+		contentType, mediaType := requestMediaType(op.RequestBody)
+		fmt.Fprintf(&b, " \\\n  -H %s", shellQuote("Content-Type: "+contentType))
+
+		body, err := json.Marshal(exampleFromSchema(mediaType.Schema, doc.Components.Schemas, 0))
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&b, " \\\n  -d %s", shellQuote(string(body)))
+	}
+
+	return b.String(), nil
+}
+
+// requestMediaType picks the media type used for examples, preferring JSON.
+func requestMediaType(body *RequestBody) (string, MediaType) {
+	if mt, ok := body.Content["application/json"]; ok {
+		return "application/json", mt
+	}
+
+	contentTypes := make([]string, 0, len(body.Content))
+	for ct := range body.Content {
+		contentTypes = append(contentTypes, ct)
+	}
+	sort.Strings(contentTypes)
+
+	if len(contentTypes) == 0 {
+		return "application/json", MediaType{}
+	}
+	return contentTypes[0], body.Content[contentTypes[0]]
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
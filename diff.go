@@ -0,0 +1,239 @@
+package router
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// ChangeType is the kind of a Change between two OpenAPI documents.
+type ChangeType string
+
+const (
+	ChangeAdded   ChangeType = "added"
+	ChangeRemoved ChangeType = "removed"
+	ChangeChanged ChangeType = "changed"
+)
+
+// Change is a difference between two OpenAPI documents, as reported by
+// DiffOpenAPI.
+type Change struct {
+	Type     ChangeType
+	Location string // e.g. "GET /users/{id}" or "#/components/schemas/User/properties/email"
+	Message  string
+	Breaking bool // Whether existing clients may break
+}
+
+func (c Change) String() string {
+	s := fmt.Sprintf("%s %s: %s", c.Type, c.Location, c.Message)
+	if c.Breaking {
+		s += " (breaking)"
+	}
+	return s
+}
+
+// DiffOpenAPI reports the changes of paths, operations, parameters, responses
+// and component schemas from the document before to the one after, sorted by
+// location. Removed operations, parameters becoming required, removed
+// properties, new required properties and type changes are flagged as
+// breaking, e.g. to fail a CI build:
+//
+//	for _, change := range router.DiffOpenAPI(published, r.OpenAPI()) {
+//		if change.Breaking {
+//			log.Fatal(change)
+//		}
+//	}
+func DiffOpenAPI(before, after *OpenAPI) []Change {
+	d := &differ{}
+
+	for _, path := range sortedUnion(before.Paths, after.Paths) {
+		beforeItem, afterItem := before.Paths[path], after.Paths[path]
+		methods := append(beforeItem.Methods(), afterItem.Methods()...)
+		slices.Sort(methods)
+		for _, method := range slices.Compact(methods) {
+			d.operation(method+" "+path, beforeItem.GetMethod(method), afterItem.GetMethod(method))
+		}
+	}
+
+	for _, name := range sortedUnion(before.Components.Schemas, after.Components.Schemas) {
+		loc := "#/components/schemas/" + name
+		beforeSchema, inBefore := before.Components.Schemas[name]
+		afterSchema, inAfter := after.Components.Schemas[name]
+		switch {
+		case !inAfter:
+			d.add(ChangeRemoved, loc, "schema removed", true)
+		case !inBefore:
+			d.add(ChangeAdded, loc, "schema added", false)
+		default:
+			d.schema(loc, beforeSchema, afterSchema)
+		}
+	}
+
+	sort.SliceStable(d.changes, func(i, j int) bool {
+		return d.changes[i].Location < d.changes[j].Location
+	})
+	return d.changes
+}
+
+type differ struct {
+	changes []Change
+}
+
+func (d *differ) add(typ ChangeType, location, message string, breaking bool) {
+	d.changes = append(d.changes, Change{Type: typ, Location: location, Message: message, Breaking: breaking})
+}
+
+func (d *differ) operation(loc string, before, after *Operation) {
+	switch {
+	case before == nil && after == nil:
+		return
+	case after == nil:
+		d.add(ChangeRemoved, loc, "operation removed", true)
+		return
+	case before == nil:
+		d.add(ChangeAdded, loc, "operation added", false)
+		return
+	}
+
+	beforeParams, afterParams := paramsByKey(before.Parameters), paramsByKey(after.Parameters)
+	for _, key := range sortedUnion(beforeParams, afterParams) {
+		paramLoc := loc + " parameter " + key
+		beforeParam, inBefore := beforeParams[key]
+		afterParam, inAfter := afterParams[key]
+		switch {
+		case !inAfter:
+			d.add(ChangeRemoved, paramLoc, "parameter removed", false)
+		case !inBefore:
+			if afterParam.Required {
+				d.add(ChangeAdded, paramLoc, "required parameter added", true)
+			} else {
+				d.add(ChangeAdded, paramLoc, "optional parameter added", false)
+			}
+		default:
+			if !beforeParam.Required && afterParam.Required {
+				d.add(ChangeChanged, paramLoc, "parameter became required", true)
+			} else if beforeParam.Required && !afterParam.Required {
+				d.add(ChangeChanged, paramLoc, "parameter became optional", false)
+			}
+			if beforeParam.Schema != nil && afterParam.Schema != nil {
+				d.schema(paramLoc, *beforeParam.Schema, *afterParam.Schema)
+			}
+		}
+	}
+
+	switch {
+	case before.RequestBody != nil && after.RequestBody == nil:
+		d.add(ChangeRemoved, loc+" request body", "request body removed", false)
+	case before.RequestBody == nil && after.RequestBody != nil:
+		d.add(ChangeAdded, loc+" request body", "request body added", after.RequestBody.Required)
+	case before.RequestBody != nil:
+		d.content(loc+" request body", before.RequestBody.Content, after.RequestBody.Content)
+	}
+
+	for _, code := range sortedUnion(before.Responses, after.Responses) {
+		resLoc := loc + " response " + code
+		beforeRes, inBefore := before.Responses[code]
+		afterRes, inAfter := after.Responses[code]
+		switch {
+		case !inAfter:
+			d.add(ChangeRemoved, resLoc, "response removed", true)
+		case !inBefore:
+			d.add(ChangeAdded, resLoc, "response added", false)
+		default:
+			d.content(resLoc, beforeRes.Content, afterRes.Content)
+		}
+	}
+}
+
+func (d *differ) content(loc string, before, after map[string]MediaType) {
+	for _, mt := range sortedUnion(before, after) {
+		beforeMedia, inBefore := before[mt]
+		afterMedia, inAfter := after[mt]
+		switch {
+		case !inAfter:
+			d.add(ChangeRemoved, loc+" "+mt, "media type removed", true)
+		case !inBefore:
+			d.add(ChangeAdded, loc+" "+mt, "media type added", false)
+		case beforeMedia.Schema != nil && afterMedia.Schema != nil:
+			d.schema(loc+" "+mt, *beforeMedia.Schema, *afterMedia.Schema)
+		}
+	}
+}
+
+func (d *differ) schema(loc string, before, after Schema) {
+	if beforeType, afterType := schemaTypeName(before), schemaTypeName(after); beforeType != afterType {
+		d.add(ChangeChanged, loc, fmt.Sprintf("type changed from %s to %s", beforeType, afterType), true)
+		return
+	}
+
+	for _, name := range sortedUnion(before.Properties, after.Properties) {
+		propLoc := loc + "/properties/" + name
+		beforeProp, inBefore := before.Properties[name]
+		afterProp, inAfter := after.Properties[name]
+		required := slices.Contains(after.Required, name)
+		switch {
+		case !inAfter:
+			d.add(ChangeRemoved, propLoc, "property removed", true)
+		case !inBefore:
+			if required {
+				d.add(ChangeAdded, propLoc, "required property added", true)
+			} else {
+				d.add(ChangeAdded, propLoc, "optional property added", false)
+			}
+		default:
+			if !slices.Contains(before.Required, name) && required {
+				d.add(ChangeChanged, propLoc, "property became required", true)
+			} else if slices.Contains(before.Required, name) && !required {
+				d.add(ChangeChanged, propLoc, "property became optional", false)
+			}
+			d.schema(propLoc, beforeProp, afterProp)
+		}
+	}
+
+	if before.Items != nil && after.Items != nil {
+		d.schema(loc+"/items", *before.Items, *after.Items)
+	}
+	if before.AdditionalProperties != nil && after.AdditionalProperties != nil {
+		d.schema(loc+"/additionalProperties", *before.AdditionalProperties, *after.AdditionalProperties)
+	}
+}
+
+// schemaTypeName describes the type of a schema, the referenced schema name
+// for references, including the nullable ones wrapped in allOf.
+func schemaTypeName(s Schema) string {
+	if len(s.AllOf) == 1 && s.Type == "" {
+		s = s.AllOf[0]
+	}
+	if s.Ref != "" {
+		return s.Ref[strings.LastIndex(s.Ref, "/")+1:]
+	}
+	if s.Format != "" {
+		return s.Type + " (" + s.Format + ")"
+	}
+	if s.Type == "" {
+		return "any"
+	}
+	return s.Type
+}
+
+func paramsByKey(params []Parameter) map[string]Parameter {
+	m := make(map[string]Parameter, len(params))
+	for _, p := range params {
+		m[p.In+" "+p.Name] = p
+	}
+	return m
+}
+
+// sortedUnion returns the sorted keys present in either map.
+func sortedUnion[V any](a, b map[string]V) []string {
+	keys := slices.Collect(maps.Keys(a))
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	slices.Sort(keys)
+	return keys
+}
@@ -0,0 +1,119 @@
+package router
+
+import (
+	"bytes"
+	"embed"
+	"html/template"
+	"net/http"
+	"strings"
+)
+
+//go:embed explorer reference
+var docsFS embed.FS
+
+var (
+	explorerTemplate  = template.Must(template.ParseFS(docsFS, "explorer/index.html"))
+	referenceTemplate = template.Must(template.ParseFS(docsFS, "reference/index.html"))
+)
+
+// ServeOpenAPI registers a GET route serving the OpenAPI document as JSON. The
+// given middlewares only wrap this route, inside the router's middlewares, e.g.
+// to protect the documentation with authentication.
+func (r *Router) ServeOpenAPI(pattern string, middlewares ...Middleware) {
+	r.serveDocs(pattern, openAPIHandler(r.OpenAPI), middlewares)
+}
+
+// openAPIHandler returns a handler encoding the document returned by doc as
+// JSON, following the JSON policy of the router. Without a policy set with
+// SetJSONConfig the document is indented with two spaces, with one its Indent
+// applies, so an empty Indent serves a compact document.
+func openAPIHandler(doc func() *OpenAPI) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		config := JSONConfig{Indent: "  "}
+		if c, ok := req.Context().Value(jsonConfigKey{}).(*JSONConfig); ok {
+			config = *c
+		}
+
+		var out bytes.Buffer
+		if err := config.encoder(&out).Encode(doc()); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(out.Bytes())
+	})
+}
+
+// ServeAPIExplorer registers a GET route serving a minimal API explorer page at
+// path, and the OpenAPI document it displays at path + "/openapi.json". The
+// explorer is a small dependency free viewer listing the operations with their
+// parameters, request bodies and responses; it is not Swagger UI and offers no
+// try-it-out or authorization. Point Swagger UI or any other tool at a document
+// served with ServeOpenAPI for those. The page and its assets are embedded,
+// nothing is loaded from a CDN. The given middlewares wrap both routes, like
+// with ServeOpenAPI.
+func (r *Router) ServeAPIExplorer(path string, middlewares ...Middleware) {
+	path = strings.TrimSuffix(path, "/")
+	specPath := path + "/openapi.json"
+
+	r.ServeOpenAPI(specPath, middlewares...)
+	page := r.docsPage(explorerTemplate, "explorer/explorer.css", "explorer/explorer.js", r.basePath+specPath)
+	r.serveDocs(path, page, middlewares)
+}
+
+// ServeAPIReference registers a GET route serving a minimal, read only API
+// reference page at path, rendering the OpenAPI document fetched from specURL
+// as a menu of operations next to their descriptions. It is a small dependency
+// free renderer, not ReDoc. When specURL is empty the document is served at
+// path + "/openapi.json". The page and its assets are embedded, nothing is
+// loaded from a CDN. The given middlewares wrap the registered routes.
+func (r *Router) ServeAPIReference(path, specURL string, middlewares ...Middleware) {
+	path = strings.TrimSuffix(path, "/")
+	if specURL == "" {
+		specPath := path + "/openapi.json"
+		r.ServeOpenAPI(specPath, middlewares...)
+		specURL = r.basePath + specPath
+	}
+
+	page := r.docsPage(referenceTemplate, "reference/reference.css", "reference/reference.js", specURL)
+	r.serveDocs(path, page, middlewares)
+}
+
+// docsPage returns a handler rendering a documentation page template with the
+// embedded CSS and JavaScript assets inlined.
+func (r *Router) docsPage(tmpl *template.Template, cssFile, jsFile, specURL string) http.Handler {
+	css, _ := docsFS.ReadFile(cssFile)
+	js, _ := docsFS.ReadFile(jsFile)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		var out bytes.Buffer
+		err := tmpl.Execute(&out, map[string]any{
+			"Title":   r.OpenAPI().Info.Title,
+			"SpecURL": specURL,
+			"CSS":     template.CSS(css),
+			"JS":      template.JS(js),
+		})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write(out.Bytes())
+	})
+}
+
+// serveDocs registers a GET route for a documentation handler wrapped with the
+// given route specific middlewares, inside the asset middlewares.
+func (r *Router) serveDocs(pattern string, handler http.Handler, middlewares []Middleware) {
+	if r.basePath != "" {
+		pattern = r.basePath + pattern
+	}
+
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+
+	r.registerRoute(http.MethodGet, pattern, r.wrapAsset(handler))
+}
@@ -0,0 +1,175 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Encoder writes a value in the media type it was registered for.
+type Encoder func(w io.Writer, v any) error
+
+// ErrNotAcceptable is returned by Respond when the Accept header of the request
+// matches no registered encoder and no default encoder is set.
+var ErrNotAcceptable = errors.New("router: no encoder matches the Accept header")
+
+// encoders is the content negotiation registry of a router. JSON is always
+// available, encoded following the router's JSONConfig unless overridden.
+type encoders struct {
+	byType      map[string]Encoder
+	order       []string
+	defaultType string
+}
+
+type encodersKey struct{}
+
+// RegisterEncoder registers the encoder Respond uses for the media type.
+func (r *Router) RegisterEncoder(mediaType string, encoder Encoder) {
+	e := r.rootParent().contentEncoders()
+	if _, ok := e.byType[mediaType]; !ok {
+		e.order = append(e.order, mediaType)
+	}
+	e.byType[mediaType] = encoder
+}
+
+// SetDefaultEncoder sets the media type Respond falls back to when the Accept
+// header matches no registered encoder. Without a default such requests are
+// answered with 406 Not Acceptable.
+func (r *Router) SetDefaultEncoder(mediaType string) {
+	r.rootParent().contentEncoders().defaultType = mediaType
+}
+
+func (r *Router) contentEncoders() *encoders {
+	if r.encoders == nil {
+		r.encoders = &encoders{byType: make(map[string]Encoder)}
+	}
+	return r.encoders
+}
+
+// Respond writes v with the given status code, in the media type negotiated
+// from the Accept header among the encoders registered on the router serving
+// the request. JSON is always available. When nothing matches, the default
+// encoder is used, or 406 Not Acceptable is written and ErrNotAcceptable
+// returned.
+func Respond(w http.ResponseWriter, req *http.Request, status int, v any) error {
+	e, _ := req.Context().Value(encodersKey{}).(*encoders)
+	if e == nil {
+		e = &encoders{}
+	}
+
+	mediaType, ok := e.negotiate(req.Header.Get("Accept"))
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusNotAcceptable), http.StatusNotAcceptable)
+		return ErrNotAcceptable
+	}
+
+	encoder, ok := e.byType[mediaType]
+	if !ok {
+		// the built-in JSON encoder
+		encoder = func(w io.Writer, v any) error {
+			return jsonConfigFrom(req).encoder(w).Encode(v)
+		}
+	}
+
+	w.Header().Set("Content-Type", mediaType)
+	w.WriteHeader(status)
+
+	return encoder(w, v)
+}
+
+// negotiate returns the registered media type preferred by the Accept header.
+func (e *encoders) negotiate(accept string) (string, bool) {
+	available := e.order
+	if !slices.Contains(available, "application/json") {
+		available = append(slices.Clone(available), "application/json")
+	}
+
+	preferred := e.defaultType
+	if preferred == "" {
+		preferred = "application/json"
+	}
+
+	if strings.TrimSpace(accept) == "" {
+		return preferred, true
+	}
+
+	for _, accepted := range acceptedMediaTypes(accept) {
+		switch {
+		case accepted == "*/*":
+			return preferred, true
+		case strings.HasSuffix(accepted, "/*"):
+			prefix := strings.TrimSuffix(accepted, "*")
+			if strings.HasPrefix(preferred, prefix) {
+				return preferred, true
+			}
+			for _, mt := range available {
+				if strings.HasPrefix(mt, prefix) {
+					return mt, true
+				}
+			}
+		case slices.Contains(available, accepted):
+			return accepted, true
+		}
+	}
+
+	if e.defaultType != "" {
+		return e.defaultType, true
+	}
+	return "", false
+}
+
+// acceptedMediaTypes returns the media types of an Accept header by decreasing
+// quality, leaving out those with a quality of zero.
+func acceptedMediaTypes(accept string) []string {
+	type accepted struct {
+		mediaType string
+		quality   float64
+	}
+
+	var types []accepted
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, _ := strings.Cut(part, ";")
+		mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+		if mediaType == "" {
+			continue
+		}
+
+		quality := 1.0
+		for _, param := range strings.Split(params, ";") {
+			key, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+			if key == "q" {
+				if q, err := strconv.ParseFloat(value, 64); err == nil {
+					quality = q
+				}
+			}
+		}
+
+		if quality > 0 {
+			types = append(types, accepted{mediaType, quality})
+		}
+	}
+
+	slices.SortStableFunc(types, func(a, b accepted) int {
+		switch {
+		case a.quality > b.quality:
+			return -1
+		case a.quality < b.quality:
+			return 1
+		}
+		return 0
+	})
+
+	mediaTypes := make([]string, len(types))
+	for i, t := range types {
+		mediaTypes[i] = t.mediaType
+	}
+	return mediaTypes
+}
+
+func withEncoders(req *http.Request, e *encoders) *http.Request {
+	return req.WithContext(context.WithValue(req.Context(), encodersKey{}, e))
+}
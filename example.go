@@ -0,0 +1,70 @@
+package router
+
+import "strings"
+
+// maxExampleDepth bounds the recursion of example generation for
+// self-referencing schemas.
+const maxExampleDepth = 8
+
+// exampleFromSchema builds a representative value for the schema, resolving
+// component references against the given schemas.
+func exampleFromSchema(s *Schema, schemas map[string]Schema, depth int) any {
+	if s == nil || depth > maxExampleDepth {
+		return nil
+	}
+
+	if s.Default != nil {
+		return s.Default
+	}
+	if len(s.Enum) > 0 {
+		return s.Enum[0]
+	}
+
+	if name, ok := strings.CutPrefix(s.Ref, "#/components/schemas/"); ok {
+		ref, exists := schemas[name]
+		if !exists {
+			return nil
+		}
+		return exampleFromSchema(&ref, schemas, depth+1)
+	}
+	if len(s.AllOf) == 1 && s.Type == "" {
+		return exampleFromSchema(&s.AllOf[0], schemas, depth) // nullable reference
+	}
+
+	switch s.Type {
+	case "object":
+		obj := make(map[string]any, len(s.Properties))
+		for name, prop := range s.Properties {
+			obj[name] = exampleFromSchema(&prop, schemas, depth+1)
+		}
+		if len(obj) == 0 && s.AdditionalProperties != nil {
+			obj["key"] = exampleFromSchema(s.AdditionalProperties, schemas, depth+1)
+		}
+		return obj
+	case "array":
+		if s.Items == nil {
+			return []any{}
+		}
+		return []any{exampleFromSchema(s.Items, schemas, depth+1)}
+	case "integer":
+		return 0
+	case "number":
+		return 0.0
+	case "boolean":
+		return true
+	case "string":
+		switch s.Format {
+		case "date-time":
+			return "2006-01-02T15:04:05Z"
+		case "date":
+			return "2006-01-02"
+		case "uuid":
+			return "00000000-0000-0000-0000-000000000000"
+		case "email":
+			return "user@example.com"
+		}
+		return "string"
+	}
+
+	return nil
+}
@@ -0,0 +1,209 @@
+(function () {
+	"use strict";
+
+	var methods = ["get", "put", "post", "delete", "options", "head", "patch", "trace"];
+	var root = document.getElementById("api-explorer");
+
+	function el(tag, attrs, children) {
+		var node = document.createElement(tag);
+		Object.keys(attrs || {}).forEach(function (key) {
+			node.setAttribute(key, attrs[key]);
+		});
+		(children || []).forEach(function (child) {
+			if (child === null || child === undefined) {
+				return;
+			}
+			node.appendChild(typeof child === "string" ? document.createTextNode(child) : child);
+		});
+		return node;
+	}
+
+	// resolve follows local $ref pointers, keeping cyclic references as they are.
+	function resolve(spec, value, seen) {
+		seen = seen || [];
+		if (Array.isArray(value)) {
+			return value.map(function (item) { return resolve(spec, item, seen); });
+		}
+		if (!value || typeof value !== "object") {
+			return value;
+		}
+		if (typeof value.$ref === "string" && value.$ref.indexOf("#/") === 0) {
+			if (seen.indexOf(value.$ref) >= 0) {
+				return value;
+			}
+			var target = value.$ref.slice(2).split("/").reduce(function (node, key) {
+				return node && node[key.replace(/~1/g, "/").replace(/~0/g, "~")];
+			}, spec);
+			return target === undefined ? value : resolve(spec, target, seen.concat(value.$ref));
+		}
+		var out = {};
+		Object.keys(value).forEach(function (key) {
+			out[key] = resolve(spec, value[key], seen);
+		});
+		return out;
+	}
+
+	function json(value) {
+		return el("pre", {}, [JSON.stringify(value, null, 2)]);
+	}
+
+	function parameters(op) {
+		var rows = (op.parameters || []).map(function (param) {
+			var input = el("input", { "data-name": param.name, "data-in": param.in, placeholder: param.name });
+			if (param.schema && param.schema.default !== undefined) {
+				input.value = param.schema.default;
+			}
+			return el("tr", {}, [
+				el("td", {}, [param.name + (param.required ? " *" : "")]),
+				el("td", {}, [param.in]),
+				el("td", { class: "description" }, [param.description || ""]),
+				el("td", {}, [input])
+			]);
+		});
+		if (rows.length === 0) {
+			return null;
+		}
+		return el("div", {}, [
+			el("h4", {}, ["Parameters"]),
+			el("table", {}, [el("tr", {}, [el("th", {}, ["Name"]), el("th", {}, ["In"]), el("th", {}, ["Description"]), el("th", {}, ["Value"])])].concat(rows))
+		]);
+	}
+
+	function requestBody(op) {
+		if (!op.requestBody || !op.requestBody.content) {
+			return null;
+		}
+		var types = Object.keys(op.requestBody.content);
+		return el("div", {}, [
+			el("h4", {}, ["Request body " + types.join(", ")]),
+			json(op.requestBody.content[types[0]].schema || {}),
+			el("textarea", { rows: "6", "data-body": types[0] || "application/json" }, [])
+		]);
+	}
+
+	function responses(op) {
+		var rows = Object.keys(op.responses || {}).map(function (code) {
+			var res = op.responses[code];
+			var content = res.content || {};
+			return el("tr", {}, [
+				el("td", {}, [code]),
+				el("td", { class: "description" }, [res.description || ""]),
+				el("td", {}, Object.keys(content).map(function (type) {
+					return el("div", {}, [type, json(content[type].example !== undefined ? content[type].example : content[type].schema || {})]);
+				}))
+			]);
+		});
+		return el("div", {}, [
+			el("h4", {}, ["Responses"]),
+			el("table", {}, [el("tr", {}, [el("th", {}, ["Code"]), el("th", {}, ["Description"]), el("th", {}, ["Content"])])].concat(rows))
+		]);
+	}
+
+	function execute(method, path, block, output) {
+		var query = [];
+		var headers = {};
+		var url = path;
+		block.querySelectorAll("input[data-name]").forEach(function (input) {
+			var name = input.getAttribute("data-name");
+			if (input.value === "") {
+				return;
+			}
+			switch (input.getAttribute("data-in")) {
+			case "path":
+				url = url.replace(new RegExp("{" + name + "(\\.\\.\\.)?}"), encodeURIComponent(input.value));
+				break;
+			case "query":
+				query.push(encodeURIComponent(name) + "=" + encodeURIComponent(input.value));
+				break;
+			case "header":
+				headers[name] = input.value;
+				break;
+			}
+		});
+		if (query.length > 0) {
+			url += "?" + query.join("&");
+		}
+
+		var init = { method: method.toUpperCase(), headers: headers };
+		var body = block.querySelector("textarea[data-body]");
+		if (body && body.value !== "") {
+			headers["Content-Type"] = body.getAttribute("data-body");
+			init.body = body.value;
+		}
+
+		output.textContent = init.method + " " + url + "\n…";
+		fetch(url, init).then(function (res) {
+			return res.text().then(function (text) {
+				output.textContent = init.method + " " + url + "\n" + res.status + " " + res.statusText + "\n\n" + text;
+			});
+		}).catch(function (err) {
+			output.textContent = init.method + " " + url + "\n" + err;
+		});
+	}
+
+	function operation(spec, method, path, op) {
+		var output = el("pre", {}, []);
+		var button = el("button", { type: "button" }, ["Execute"]);
+		var block = el("details", { class: "opblock " + method + (op.deprecated ? " deprecated" : "") }, [
+			el("summary", {}, [el("span", { class: "method" }, [method]), el("span", { class: "path" }, [path]), el("span", {}, [op.summary || ""])]),
+			el("div", { class: "body" }, [
+				op.description ? el("p", { class: "description" }, [op.description]) : null,
+				parameters(op),
+				requestBody(op),
+				responses(op),
+				button,
+				output
+			])
+		]);
+		button.addEventListener("click", function () {
+			execute(method, path, block, output);
+		});
+		return block;
+	}
+
+	function render(spec) {
+		var resolved = resolve(spec, spec);
+		var info = resolved.info || {};
+		var groups = {};
+		var order = [];
+
+		Object.keys(resolved.paths || {}).forEach(function (path) {
+			var item = resolved.paths[path];
+			methods.forEach(function (method) {
+				var op = item[method];
+				if (!op) {
+					return;
+				}
+				op.parameters = (item.parameters || []).concat(op.parameters || []);
+				var tag = (op.tags && op.tags[0]) || "default";
+				if (!groups[tag]) {
+					groups[tag] = [];
+					order.push(tag);
+				}
+				groups[tag].push(operation(resolved, method, path, op));
+			});
+		});
+
+		root.textContent = "";
+		root.appendChild(el("h1", {}, [info.title || "API", el("small", {}, [info.version || ""])]));
+		if (info.description) {
+			root.appendChild(el("p", { class: "description" }, [info.description]));
+		}
+		order.forEach(function (tag) {
+			root.appendChild(el("h2", {}, [tag]));
+			groups[tag].forEach(function (block) {
+				root.appendChild(block);
+			});
+		});
+	}
+
+	fetch(window.apiExplorerSpecURL).then(function (res) {
+		if (!res.ok) {
+			throw new Error(res.status + " " + res.statusText);
+		}
+		return res.json();
+	}).then(render).catch(function (err) {
+		root.textContent = "";
+		root.appendChild(el("p", { class: "error" }, ["Failed to load " + window.apiExplorerSpecURL + ": " + err.message]));
+	});
+})();
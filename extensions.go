@@ -0,0 +1,168 @@
+package router
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// MarshalJSON flattens the vendor extensions alongside the standard fields.
+// Fields introduced in OpenAPI 3.1 are omitted from documents of earlier
+// versions, while 3.1 documents describe nullable schemas the JSON Schema way.
+func (o OpenAPI) MarshalJSON() ([]byte, error) {
+	if openAPI31(o.Openapi) {
+		o = o.jsonSchemaNullable()
+	} else {
+		o.Info.Summary = ""
+		o.JSONSchemaDialect = ""
+	}
+
+	type openAPI OpenAPI
+	return marshalWithExtensions(openAPI(o), o.Extensions)
+}
+
+// MarshalJSON flattens the vendor extensions alongside the standard fields.
+func (i Info) MarshalJSON() ([]byte, error) {
+	type info Info
+	return marshalWithExtensions(info(i), i.Extensions)
+}
+
+// MarshalJSON flattens the vendor extensions alongside the standard fields.
+func (o Operation) MarshalJSON() ([]byte, error) {
+	type operation Operation
+	return marshalWithExtensions(operation(o), o.Extensions)
+}
+
+// MarshalJSON flattens the vendor extensions alongside the standard fields.
+func (s Schema) MarshalJSON() ([]byte, error) {
+	type schema Schema
+	if s.nullType {
+		return marshalWithExtensions(struct {
+			schema
+			Type []string `json:"type"`
+		}{schema(s), []string{s.Type, "null"}}, s.Extensions)
+	}
+	return marshalWithExtensions(schema(s), s.Extensions)
+}
+
+// jsonSchemaNullable returns a copy of the document whose nullable schemas are
+// expressed as in JSON Schema 2020-12, which OpenAPI 3.1 follows: typed schemas
+// get a type array including "null", references a oneOf with a null schema.
+func (o OpenAPI) jsonSchemaNullable() OpenAPI {
+	var nullable func(*Schema) *Schema
+	nullable = func(s *Schema) *Schema {
+		if s == nil {
+			return nil
+		}
+
+		c := mapSchemaChildren(*s, nullable)
+		if !c.Nullable {
+			return &c
+		}
+
+		c.Nullable = false
+		switch {
+		case c.Ref != "":
+			c.OneOf = []Schema{{Ref: c.Ref}, {Type: "null"}}
+			c.Ref = ""
+		case len(c.AllOf) == 1 && c.AllOf[0].Ref != "":
+			c.OneOf = []Schema{c.AllOf[0], {Type: "null"}}
+			c.AllOf = nil
+		case c.Type != "":
+			c.nullType = true
+		}
+		return &c
+	}
+
+	if o.Paths != nil {
+		paths := make(map[string]PathItem, len(o.Paths))
+		for path, item := range o.Paths {
+			paths[path] = mapPathItemOperations(item, func(op *Operation) *Operation {
+				return mapOperationSchemas(op, nullable)
+			})
+		}
+		o.Paths = paths
+	}
+
+	if o.Components.Schemas != nil {
+		schemas := make(map[string]Schema, len(o.Components.Schemas))
+		for name, s := range o.Components.Schemas {
+			schemas[name] = *nullable(&s)
+		}
+		o.Components.Schemas = schemas
+	}
+
+	return o
+}
+
+// openAPI31 reports whether the OpenAPI version is 3.1 or later.
+func openAPI31(version string) bool {
+	parts := strings.SplitN(version, ".", 3)
+	major, _ := strconv.Atoi(parts[0])
+	minor := 0
+	if len(parts) > 1 {
+		minor, _ = strconv.Atoi(parts[1])
+	}
+	return major > 3 || major == 3 && minor >= 1
+}
+
+// marshalWithExtensions encodes v as a JSON object and appends each extension
+// as an additional member, sorted by key. Extension keys must start with x-.
+// HTML characters are left unescaped, the enclosing encoder escapes them when
+// configured to, so markdown descriptions survive JSONConfig.DisableHTMLEscape.
+func marshalWithExtensions(v any, extensions map[string]any) ([]byte, error) {
+	data, err := marshalNoEscape(v)
+	if err != nil || len(extensions) == 0 {
+		return data, err
+	}
+
+	keys := make([]string, 0, len(extensions))
+	for k := range extensions {
+		if !strings.HasPrefix(k, "x-") {
+			return nil, fmt.Errorf("router: invalid extension %q, vendor extensions must start with x-", k)
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var buf bytes.Buffer
+	buf.Write(data[:len(data)-1]) // strip the closing brace
+
+	empty := bytes.Equal(bytes.TrimSpace(data), []byte("{}"))
+	for _, k := range keys {
+		key, err := json.Marshal(k)
+		if err != nil {
+			return nil, err
+		}
+		value, err := marshalNoEscape(extensions[k])
+		if err != nil {
+			return nil, err
+		}
+
+		if !empty {
+			buf.WriteByte(',')
+		}
+		empty = false
+
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+// marshalNoEscape is json.Marshal without HTML escaping.
+func marshalNoEscape(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
@@ -0,0 +1,82 @@
+package router
+
+import "strings"
+
+// OpenAPIFiltered returns a copy of the OpenAPI document holding only the
+// operations for which keep returns true, e.g. to publish a public document
+// next to the full internal one:
+//
+//	public := r.OpenAPIFiltered(func(op *Operation) bool {
+//		return !slices.Contains(op.Tags, "internal")
+//	})
+//
+// Paths left without operations are removed, as are the component schemas no
+// longer referenced by the remaining operations.
+func (r *Router) OpenAPIFiltered(keep func(*Operation) bool) *OpenAPI {
+	doc := r.OpenAPI().Clone()
+
+	paths := make(map[string]PathItem, len(doc.Paths))
+	for path, item := range doc.Paths {
+		item = mapPathItemOperations(item, func(op *Operation) *Operation {
+			if op == nil || !keep(op) {
+				return nil
+			}
+			return op
+		})
+		if len(item.Methods()) > 0 {
+			paths[path] = item
+		}
+	}
+	doc.Paths = paths
+	doc.Components.Schemas = referencedSchemas(doc)
+
+	return doc
+}
+
+// ServeOpenAPIFiltered registers a GET route serving the document returned by
+// OpenAPIFiltered as JSON, like ServeOpenAPI.
+func (r *Router) ServeOpenAPIFiltered(pattern string, keep func(*Operation) bool, middlewares ...Middleware) {
+	r.serveDocs(pattern, openAPIHandler(func() *OpenAPI {
+		return r.OpenAPIFiltered(keep)
+	}), middlewares)
+}
+
+// referencedSchemas returns the component schemas referenced, directly or
+// through other components, by the operations of the document.
+func referencedSchemas(doc *OpenAPI) map[string]Schema {
+	var (
+		schemas = make(map[string]Schema)
+		pending []string
+	)
+
+	visit := func(ref string) {
+		name, ok := strings.CutPrefix(ref, "#/components/schemas/")
+		if !ok {
+			return
+		}
+		if _, seen := schemas[name]; seen {
+			return
+		}
+		if schema, exists := doc.Components.Schemas[name]; exists {
+			schemas[name] = schema
+			pending = append(pending, name)
+		}
+	}
+
+	for _, item := range doc.Paths {
+		for _, method := range item.Methods() {
+			mapOperationSchemas(item.GetMethod(method), func(s *Schema) *Schema {
+				walkRefs(s, visit)
+				return s
+			})
+		}
+	}
+
+	for len(pending) > 0 {
+		schema := schemas[pending[0]]
+		pending = pending[1:]
+		walkRefs(&schema, visit)
+	}
+
+	return schemas
+}
@@ -0,0 +1,134 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"reflect"
+	"sync"
+)
+
+// DefaultMaxMultipartMemory is the number of bytes of a multipart body BindForm
+// keeps in memory before storing file parts in temporary files.
+var DefaultMaxMultipartMemory int64 = 32 << 20
+
+var (
+	fileHeaderType  = reflect.TypeOf((*multipart.FileHeader)(nil))
+	fileHeadersType = reflect.TypeOf([]*multipart.FileHeader(nil))
+)
+
+type multipartStateKey struct{}
+
+// multipartState holds the multipart limit of the router serving a request and
+// the forms parsed while serving it, whose temporary files are removed when
+// the request is done.
+type multipartState struct {
+	maxMemory int64
+
+	mu    sync.Mutex
+	forms []*multipart.Form
+}
+
+func (s *multipartState) add(form *multipart.Form) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.forms = append(s.forms, form)
+}
+
+func (s *multipartState) cleanup() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, form := range s.forms {
+		_ = form.RemoveAll()
+	}
+	s.forms = nil
+}
+
+// SetMaxMultipartMemory sets the number of bytes of a multipart body BindForm
+// keeps in memory, DefaultMaxMultipartMemory by default. Larger file parts are
+// stored in temporary files, removed once the request is served.
+func (r *Router) SetMaxMultipartMemory(n int64) {
+	r.rootParent().maxMultipartMemory = n
+}
+
+func withMultipartState(req *http.Request, maxMemory int64) (*http.Request, *multipartState) {
+	if maxMemory <= 0 {
+		maxMemory = DefaultMaxMultipartMemory
+	}
+	state := &multipartState{maxMemory: maxMemory}
+	return req.WithContext(context.WithValue(req.Context(), multipartStateKey{}, state)), state
+}
+
+// BindForm decodes the form of a URL encoded or multipart request body into
+// the struct pointed to by dst. Fields are matched by their `form` tag, or by
+// their name when the tag is absent; fields tagged `form:"-"` are skipped.
+// Uploaded files bind to *multipart.FileHeader and []*multipart.FileHeader
+// fields. Multipart bodies are parsed with the limit set by
+// SetMaxMultipartMemory.
+func BindForm(req *http.Request, dst any) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return errors.New("router: BindForm requires a pointer to a struct")
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if mediaType != "multipart/form-data" {
+		if err := req.ParseForm(); err != nil {
+			return err
+		}
+		return bindValues(req.PostForm, v.Elem(), "form")
+	}
+
+	state, _ := req.Context().Value(multipartStateKey{}).(*multipartState)
+	maxMemory := DefaultMaxMultipartMemory
+	if state != nil {
+		maxMemory = state.maxMemory
+	}
+
+	if req.MultipartForm == nil {
+		if err := req.ParseMultipartForm(maxMemory); err != nil {
+			return err
+		}
+		if state != nil {
+			state.add(req.MultipartForm)
+		}
+	}
+
+	if err := bindValues(req.PostForm, v.Elem(), "form"); err != nil {
+		return err
+	}
+	bindFiles(req.MultipartForm.File, v.Elem())
+	return nil
+}
+
+// bindFiles sets the file fields of v to the uploaded files of the same name.
+func bindFiles(files map[string][]*multipart.FileHeader, v reflect.Value) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() || (field.Type != fileHeaderType && field.Type != fileHeadersType) {
+			continue
+		}
+
+		name := field.Tag.Get("form")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		headers := files[name]
+		if len(headers) == 0 {
+			continue
+		}
+
+		if field.Type == fileHeaderType {
+			v.Field(i).Set(reflect.ValueOf(headers[0]))
+		} else {
+			v.Field(i).Set(reflect.ValueOf(headers))
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package router
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// AutoHead registers a HEAD route next to every GET route registered
+// afterwards. The HEAD route runs the GET handler and discards the body, while
+// the Content-Length reflects the body the GET request would have received. It
+// is not documented as a separate operation.
+func (r *Router) AutoHead(auto bool) {
+	r.autoHead = auto
+}
+
+// headHandler serves HEAD requests with a GET handler.
+func headHandler(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		hw := &headWriter{ResponseWriter: w}
+		handler.ServeHTTP(hw, req)
+		hw.finish()
+	})
+}
+
+// headWriter discards the body and defers the header until the handler
+// returns, so the length of the discarded body can be reported.
+type headWriter struct {
+	http.ResponseWriter
+	statusCode  int
+	length      int
+	wroteHeader bool
+}
+
+func (w *headWriter) WriteHeader(statusCode int) {
+	if w.statusCode == 0 {
+		w.statusCode = statusCode
+	}
+}
+
+func (w *headWriter) Write(b []byte) (int, error) {
+	if w.statusCode == 0 {
+		w.statusCode = http.StatusOK
+	}
+	w.length += len(b)
+	return len(b), nil
+}
+
+func (w *headWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *headWriter) finish() {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+
+	if w.statusCode == 0 {
+		w.statusCode = http.StatusOK
+	}
+	if w.length > 0 && w.Header().Get("Content-Length") == "" {
+		w.Header().Set("Content-Length", strconv.Itoa(w.length))
+	}
+	w.ResponseWriter.WriteHeader(w.statusCode)
+}
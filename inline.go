@@ -0,0 +1,80 @@
+package router
+
+import (
+	"maps"
+	"strings"
+)
+
+// Inline returns a copy of the document in which every reference to a
+// component schema is replaced by the schema itself, for consumers that cannot
+// resolve $ref. References that would recurse into a schema being inlined are
+// kept, so the component schemas are only retained when such cycles exist.
+func (o *OpenAPI) Inline() *OpenAPI {
+	doc := o.Clone()
+	if doc == nil {
+		return nil
+	}
+
+	var cyclic bool
+	for path, item := range doc.Paths {
+		doc.Paths[path] = mapPathItemOperations(item, func(op *Operation) *Operation {
+			return mapOperationSchemas(op, func(s *Schema) *Schema {
+				return inlineSchema(s, doc.Components.Schemas, make(map[string]bool), &cyclic)
+			})
+		})
+	}
+
+	if !cyclic {
+		doc.Components.Schemas = nil
+	}
+
+	return doc
+}
+
+// inlineOperation inlines the references of the operation using the generated
+// schemas and the existing components. It returns the generated schemas that
+// still need to be registered as components, which is only the case for cycles.
+func inlineOperation(op *Operation, generated, components map[string]Schema) (*Operation, map[string]Schema) {
+	schemas := maps.Clone(components)
+	maps.Copy(schemas, generated)
+
+	var cyclic bool
+	op = mapOperationSchemas(op, func(s *Schema) *Schema {
+		return inlineSchema(s, schemas, make(map[string]bool), &cyclic)
+	})
+
+	if cyclic {
+		return op, generated
+	}
+	return op, nil
+}
+
+// inlineSchema replaces component references in the schema by the schemas they
+// point to. References into a schema that is being inlined are kept and
+// reported through cyclic.
+func inlineSchema(s *Schema, schemas map[string]Schema, visiting map[string]bool, cyclic *bool) *Schema {
+	if s == nil {
+		return nil
+	}
+
+	if name, ok := strings.CutPrefix(s.Ref, "#/components/schemas/"); ok {
+		ref, exists := schemas[name]
+		if !exists {
+			return s
+		}
+		if visiting[name] {
+			*cyclic = true
+			return s
+		}
+
+		visiting[name] = true
+		defer delete(visiting, name)
+
+		return inlineSchema(&ref, schemas, visiting, cyclic)
+	}
+
+	c := mapSchemaChildren(*s, func(child *Schema) *Schema {
+		return inlineSchema(child, schemas, visiting, cyclic)
+	})
+	return &c
+}
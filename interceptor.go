@@ -1,15 +1,37 @@
 package router
 
 import (
+	"context"
 	"net/http"
 )
 
 const HeaderFlagDoNotIntercept = "do_not_intercept"
 
+// ResponseStats holds the final status code and number of body bytes sent to
+// the client for a request served by the router.
+type ResponseStats struct {
+	Status int // Status code sent, zero until the header is written
+	Bytes  int // Number of body bytes written
+}
+
+type responseStatsKey struct{}
+
+// ResponseStatsFrom returns the response statistics the router records for the
+// request of the context, or nil outside the router. Middlewares read them
+// after calling the next handler, so they don't need their own writer wrapper.
+func ResponseStatsFrom(ctx context.Context) *ResponseStats {
+	stats, _ := ctx.Value(responseStatsKey{}).(*ResponseStats)
+	return stats
+}
+
+// excludeHeaderWriter is the outermost writer of the router, it removes the
+// internal headers and records what reaches the client.
 type excludeHeaderWriter struct {
 	http.ResponseWriter
 
 	excludedHeaders []string
+	stats           *ResponseStats
+	wroteHeader     bool
 }
 
 func (w *excludeHeaderWriter) WriteHeader(statusCode int) {
@@ -17,9 +39,32 @@ func (w *excludeHeaderWriter) WriteHeader(statusCode int) {
 		w.Header().Del(header)
 	}
 
+	if !w.wroteHeader && statusCode >= http.StatusOK {
+		w.wroteHeader = true
+		if w.stats != nil {
+			w.stats.Status = statusCode
+		}
+	}
+
 	w.ResponseWriter.WriteHeader(statusCode)
 }
 
+func (w *excludeHeaderWriter) Write(data []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	n, err := w.ResponseWriter.Write(data)
+	if w.stats != nil {
+		w.stats.Bytes += n
+	}
+	return n, err
+}
+
+func (w *excludeHeaderWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
 type routingStatusInterceptWriter struct {
 	http.ResponseWriter
 
@@ -48,10 +93,16 @@ func (w *routingStatusInterceptWriter) WriteHeader(statusCode int) {
 	w.ResponseWriter.WriteHeader(statusCode)
 }
 
+// Write discards the body of intercepted responses, which the status handler
+// replaces, and reports it as written so handlers don't see a short write.
 func (w *routingStatusInterceptWriter) Write(data []byte) (int, error) {
 	if w.intercepted {
-		return 0, nil
+		return len(data), nil
 	}
 
 	return w.ResponseWriter.Write(data)
 }
+
+func (w *routingStatusInterceptWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,79 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+)
+
+// JSONConfig is the JSON policy shared by JSON, Bind and the OpenAPI document
+// served by ServeOpenAPI. The zero value matches the encoding/json defaults.
+type JSONConfig struct {
+	DisableHTMLEscape     bool   // Write <, > and & as is instead of escaping them
+	Indent                string // Indentation of encoded values, compact when empty
+	UseNumber             bool   // Decode numbers into json.Number instead of float64
+	DisallowUnknownFields bool   // Reject bodies with fields the destination does not declare
+}
+
+type jsonConfigKey struct{}
+
+// SetJSONConfig sets the JSON policy applied to the requests served by the
+// router.
+func (r *Router) SetJSONConfig(config JSONConfig) {
+	r.rootParent().jsonConfig = &config
+}
+
+// jsonConfigFrom returns the JSON policy of the router serving the request.
+func jsonConfigFrom(req *http.Request) JSONConfig {
+	if req != nil {
+		if config, ok := req.Context().Value(jsonConfigKey{}).(*JSONConfig); ok {
+			return *config
+		}
+	}
+	return JSONConfig{}
+}
+
+func withJSONConfig(req *http.Request, config *JSONConfig) *http.Request {
+	return req.WithContext(context.WithValue(req.Context(), jsonConfigKey{}, config))
+}
+
+func (c JSONConfig) encoder(w io.Writer) *json.Encoder {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(!c.DisableHTMLEscape)
+	if c.Indent != "" {
+		enc.SetIndent("", c.Indent)
+	}
+	return enc
+}
+
+func (c JSONConfig) decoder(r io.Reader) *json.Decoder {
+	dec := json.NewDecoder(r)
+	if c.UseNumber {
+		dec.UseNumber()
+	}
+	if c.DisallowUnknownFields {
+		dec.DisallowUnknownFields()
+	}
+	return dec
+}
+
+// JSON writes v as application/json with the given status code, following the
+// JSON policy of the router serving the request.
+func JSON(w http.ResponseWriter, req *http.Request, status int, v any) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	return jsonConfigFrom(req).encoder(w).Encode(v)
+}
+
+// Bind decodes the JSON body of the request into dst, following the JSON policy
+// of the router serving the request.
+func Bind(req *http.Request, dst any) error {
+	if req.Body == nil || req.Body == http.NoBody {
+		return errors.New("router: Bind requires a request body")
+	}
+
+	return jsonConfigFrom(req).decoder(req.Body).Decode(dst)
+}
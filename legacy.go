@@ -0,0 +1,43 @@
+package router
+
+import "strings"
+
+// withLegacyFields returns the docs with Title and Params translated into
+// Summary and Parameters. Params named after a wildcard of the pattern are
+// path parameters, the others query parameters.
+func (d Docs) withLegacyFields(pattern string) Docs {
+	if d.Title == "" && len(d.Params) == 0 {
+		return d
+	}
+
+	if d.Summary == "" {
+		d.Summary = d.Title
+	}
+	d.Title = ""
+
+	if len(d.Params) > 0 {
+		d.Parameters = append(make([]Parameter, 0, len(d.Parameters)+len(d.Params)), d.Parameters...)
+		for _, p := range d.Params {
+			typ := p.Type
+			if typ == "" {
+				typ = "string"
+			}
+
+			param := Parameter{
+				Name:        p.Name,
+				In:          "query",
+				Description: p.Description,
+				Schema:      &Schema{Type: typ},
+			}
+			if strings.Contains(pattern, "{"+p.Name+"}") || strings.Contains(pattern, "{"+p.Name+"...}") {
+				param.In = "path"
+				param.Required = true
+			}
+
+			d.Parameters = append(d.Parameters, param)
+		}
+		d.Params = nil
+	}
+
+	return d
+}
@@ -0,0 +1,23 @@
+package router
+
+import "net/http"
+
+// withMaxBodyBytes limits the request body of the handler to n bytes. Requests
+// declaring a larger Content-Length are rejected with 413 Request Entity Too
+// Large; for other requests reading past the limit fails with an
+// *http.MaxBytesError, which the handler should answer with a 413.
+func withMaxBodyBytes(handler http.Handler, n int64) http.Handler {
+	if n <= 0 {
+		return handler
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.ContentLength > n {
+			http.Error(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+			return
+		}
+
+		req.Body = http.MaxBytesReader(w, req.Body, n)
+		handler.ServeHTTP(w, req)
+	})
+}
@@ -0,0 +1,201 @@
+package router
+
+import (
+	"fmt"
+	"maps"
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// Merge registers all routes of another router under the given prefix and
+// merges its OpenAPI paths and component schemas into this router's document.
+//
+// The merged routes keep the middleware chain of the router they were
+// registered on; middlewares of this router do not apply to them. The prefix is
+// stripped from the request path before it reaches the merged handlers, so they
+// see the same paths as on their own router. Routes of Host routers keep their
+// host. Component schemas whose name is
+// already taken by a different schema are renamed and their references updated.
+// Likewise, merged operationIds already in use get a number suffix, links to
+// them included.
+func (r *Router) Merge(prefix string, other *Router) {
+	prefix = strings.TrimSuffix(r.basePath+prefix, "/")
+
+	otherRoot := other.rootParent()
+	otherRoot.mu.RLock()
+	routes := append([]route{}, otherRoot.routes...)
+	preflights := maps.Clone(otherRoot.preflights)
+	notAllowed := maps.Clone(otherRoot.notAllowed)
+	otherRoot.mu.RUnlock()
+
+	rootRouter := r.rootParent()
+	for _, rt := range routes {
+		preflight := rt.method == http.MethodOptions && preflights[rt.host+rt.pattern]
+		methodNotAllowed := rt.method == "" && notAllowed[rt.host+rt.pattern]
+		if prefix != "" {
+			rt.handler = http.StripPrefix(prefix, rt.handler)
+		}
+		if rt.host == "" {
+			rt.host = r.host
+		}
+		rt.pattern = prefix + rt.pattern
+		if preflight {
+			rootRouter.markPreflight(rt.host + rt.pattern)
+		}
+		if methodNotAllowed {
+			rootRouter.markNotAllowed(rt.host + rt.pattern)
+		}
+		rt.group = rt.group || r.parent != nil
+		rootRouter.mount(rt)
+	}
+
+	if r.openapiDocs {
+		r.mergeDocs(prefix, otherRoot)
+	}
+}
+
+func (r *Router) mergeDocs(prefix string, other *Router) {
+	rootRouter := r.rootParent()
+	if rootRouter == other {
+		return
+	}
+
+	rootRouter.mu.Lock()
+	defer rootRouter.mu.Unlock()
+	other.mu.RLock()
+	defer other.mu.RUnlock()
+
+	// merge the component schemas first, renaming on collisions
+	var (
+		renames = make(map[string]string)
+		added   = make(map[string]Schema)
+	)
+	for _, name := range slices.Sorted(maps.Keys(other.openapi.Components.Schemas)) {
+		schema := other.openapi.Components.Schemas[name]
+		existing, exists := rootRouter.openapi.Components.Schemas[name]
+		if exists && reflect.DeepEqual(existing, schema) {
+			continue
+		}
+
+		newName := name
+		for i := 2; exists; i++ {
+			newName = fmt.Sprintf("%s%d", name, i)
+			_, exists = rootRouter.openapi.Components.Schemas[newName]
+			if _, taken := added[newName]; taken {
+				exists = true // renamed schema merged before
+			}
+			if _, taken := other.openapi.Components.Schemas[newName]; taken {
+				exists = true // schema yet to be merged
+			}
+		}
+		if newName != name {
+			renames[name] = newName
+		}
+		added[newName] = schema
+	}
+
+	// references between the merged schemas must follow the renames too
+	for name, schema := range added {
+		rootRouter.openapi.Components.Schemas[name] = *renameRefs(&schema, renames)
+	}
+
+	// operationIds must stay unique too, colliding ones get a number suffix
+	operationIDs := make(map[string]bool)
+	for _, item := range rootRouter.openapi.Paths {
+		mapPathItemOperations(item, func(op *Operation) *Operation {
+			if op != nil {
+				operationIDs[op.OperationID] = true
+			}
+			return op
+		})
+	}
+	idRenames := make(map[string]string)
+	for _, path := range slices.Sorted(maps.Keys(other.openapi.Paths)) {
+		mapPathItemOperations(other.openapi.Paths[path], func(op *Operation) *Operation {
+			if op == nil || op.OperationID == "" {
+				return op
+			}
+
+			newID := op.OperationID
+			for i := 2; operationIDs[newID]; i++ {
+				newID = fmt.Sprintf("%s%d", op.OperationID, i)
+			}
+			if newID != op.OperationID {
+				idRenames[op.OperationID] = newID
+			}
+			operationIDs[newID] = true
+			return op
+		})
+	}
+
+	for path, item := range other.openapi.Paths {
+		rootRouter.openapi.Paths[prefix+path] = mapPathItemOperations(item, func(op *Operation) *Operation {
+			op = mapOperationSchemas(op, func(s *Schema) *Schema {
+				return renameRefs(s, renames)
+			})
+			if op != nil {
+				renameOperationIDs(op, idRenames)
+			}
+			return op
+		})
+	}
+
+	for stripped, pattern := range other.patternMap {
+		rootRouter.patternMap[prefix+stripped] = prefix + pattern
+	}
+	for _, path := range other.optionsPaths {
+		if rootRouter.optionsPaths == nil {
+			rootRouter.optionsPaths = make(map[string]*optionsPath)
+		}
+		merged := *path
+		merged.pattern = prefix + path.pattern
+		if merged.host == "" {
+			merged.host = r.host
+		}
+		rootRouter.optionsPaths[merged.host+strings.ReplaceAll(merged.pattern, "{$}", "")] = &merged
+	}
+}
+
+// renameOperationIDs applies the renames to the operationId of a copied
+// operation and to the operations its response links point to.
+func renameOperationIDs(op *Operation, renames map[string]string) {
+	if newID, ok := renames[op.OperationID]; ok {
+		op.OperationID = newID
+	}
+
+	for code, res := range op.Responses {
+		if res.Links == nil {
+			continue
+		}
+		links := make(map[string]Link, len(res.Links))
+		for name, link := range res.Links {
+			if newID, ok := renames[link.OperationID]; ok {
+				link.OperationID = newID
+			}
+			links[name] = link
+		}
+		res.Links = links
+		op.Responses[code] = res
+	}
+}
+
+func renameRefs(s *Schema, renames map[string]string) *Schema {
+	if s == nil {
+		return nil
+	}
+
+	c := *s
+	if name, ok := strings.CutPrefix(c.Ref, "#/components/schemas/"); ok {
+		if newName, ok := renames[name]; ok {
+			c.Ref = "#/components/schemas/" + newName
+		}
+	}
+
+	c = mapSchemaChildren(c, func(child *Schema) *Schema {
+		return renameRefs(child, renames)
+	})
+
+	return &c
+}
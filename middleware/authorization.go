@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"strings"
+)
+
+type authorizationKey struct{}
+
+type authorization struct {
+	scheme string
+	token  string
+}
+
+// ParseAuthorization returns a Middleware that parses the Authorization header
+// once, so the authentication middlewares down the chain read the scheme and
+// credentials with Authorization. Requests with a malformed Bearer or Basic
+// header, e.g. without credentials or with Basic credentials that are not a
+// base64 encoded user-id:password pair, are rejected with 400 Bad Request.
+// Other schemes, such as Digest, are not validated and keep their credentials
+// as sent. Requests without the header pass through.
+func ParseAuthorization() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header := r.Header.Get("Authorization")
+			if header == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			auth, ok := parseAuthorization(header)
+			if !ok {
+				http.Error(w, "malformed Authorization header", http.StatusBadRequest)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), authorizationKey{}, auth)))
+		})
+	}
+}
+
+// Authorization returns the scheme and credentials of the Authorization header
+// parsed by ParseAuthorization. The Bearer and Basic schemes are normalized to
+// that spelling; the token is returned as sent, still base64 encoded for Basic,
+// and holds all the parameters of other schemes.
+//
+// It lives here rather than as router.Authorization: the router package
+// imports middleware, so the middlewares building on it, such as BasicAuth,
+// could not import the router package back.
+func Authorization(r *http.Request) (scheme, token string, ok bool) {
+	auth, ok := r.Context().Value(authorizationKey{}).(authorization)
+	return auth.scheme, auth.token, ok
+}
+
+func parseAuthorization(header string) (authorization, bool) {
+	scheme, token, _ := strings.Cut(header, " ")
+	token = strings.TrimSpace(token)
+	if scheme == "" {
+		return authorization{}, false
+	}
+
+	// only the schemes normalized here are validated, others such as Digest
+	// carry comma separated parameters and pass through as sent
+	switch {
+	case strings.EqualFold(scheme, "Bearer"):
+		scheme = "Bearer"
+	case strings.EqualFold(scheme, "Basic"):
+		scheme = "Basic"
+		decoded, err := base64.StdEncoding.DecodeString(token)
+		if err != nil || !strings.Contains(string(decoded), ":") {
+			return authorization{}, false
+		}
+	default:
+		return authorization{scheme: scheme, token: token}, true
+	}
+
+	if token == "" || strings.ContainsAny(token, " \t") {
+		return authorization{}, false
+	}
+	return authorization{scheme: scheme, token: token}, true
+}
@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"net/http"
+	"strings"
+)
+
+type basicAuthUserKey struct{}
+
+// BasicAuth returns a Middleware that requires HTTP Basic credentials accepted
+// by validate. Requests with missing or invalid credentials are rejected with
+// 401 Unauthorized and a WWW-Authenticate challenge for the realm. The user of
+// accepted requests is available to the next handlers through BasicAuthUser.
+// Behind ParseAuthorization, the credentials are read from Authorization
+// instead of parsing the header again.
+func BasicAuth(realm string, validate func(user, pass string) bool) func(http.Handler) http.Handler {
+	challenge := `Basic realm="` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(realm) + `", charset="UTF-8"`
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user, pass, ok := basicCredentials(r)
+			if !ok || !validate(user, pass) {
+				w.Header().Set("WWW-Authenticate", challenge)
+				http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), basicAuthUserKey{}, user)))
+		})
+	}
+}
+
+// basicCredentials returns the Basic credentials of the request, as parsed by
+// ParseAuthorization when it ran before.
+func basicCredentials(r *http.Request) (user, pass string, ok bool) {
+	scheme, token, parsed := Authorization(r)
+	if !parsed {
+		return r.BasicAuth()
+	}
+	if scheme != "Basic" {
+		return "", "", false
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(token)
+	if err != nil {
+		return "", "", false
+	}
+	return strings.Cut(string(decoded), ":")
+}
+
+// BasicAuthUser returns the user authenticated by BasicAuth.
+func BasicAuthUser(r *http.Request) (string, bool) {
+	user, ok := r.Context().Value(basicAuthUserKey{}).(string)
+	return user, ok
+}
+
+// BasicAuthUsers returns a BasicAuth validate function accepting the given user
+// and password pairs. Credentials are compared in constant time, so response
+// times do not reveal how much of a user or password matched.
+func BasicAuthUsers(users map[string]string) func(user, pass string) bool {
+	type credentials struct{ user, pass [sha256.Size]byte }
+
+	known := make([]credentials, 0, len(users))
+	for user, pass := range users {
+		known = append(known, credentials{sha256.Sum256([]byte(user)), sha256.Sum256([]byte(pass))})
+	}
+
+	return func(user, pass string) bool {
+		// hashing gives the compared values a fixed length, and every pair is
+		// checked so the position of the match is not revealed either
+		u, p := sha256.Sum256([]byte(user)), sha256.Sum256([]byte(pass))
+
+		match := 0
+		for _, c := range known {
+			match |= subtle.ConstantTimeCompare(u[:], c.user[:]) & subtle.ConstantTimeCompare(p[:], c.pass[:])
+		}
+		return match == 1
+	}
+}
@@ -0,0 +1,112 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var gzipWriterPool = sync.Pool{
+	New: func() any {
+		return gzip.NewWriter(io.Discard)
+	},
+}
+
+// Compress gzip encodes the response body for clients that accept it.
+//
+// Any Content-Length set before compression describes the uncompressed body and
+// is removed. When Compress is wrapped by ContentLengthMiddleware the length of
+// the compressed body is computed there, otherwise the response is sent chunked.
+func Compress(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Encoding")
+
+		if r.Method == http.MethodHead || !acceptsGzip(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		cw := &compressWriter{
+			ResponseWriter: w,
+		}
+		defer cw.Close()
+
+		next.ServeHTTP(cw, r)
+	})
+}
+
+func acceptsGzip(r *http.Request) bool {
+	for _, value := range r.Header.Values("Accept-Encoding") {
+		for _, enc := range strings.Split(value, ",") {
+			enc, params, _ := strings.Cut(enc, ";")
+			if !strings.EqualFold(strings.TrimSpace(enc), "gzip") {
+				continue
+			}
+			// gzip;q=0 explicitly refuses the encoding
+			if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+				if v, err := strconv.ParseFloat(q, 64); err == nil && v == 0 {
+					return false
+				}
+			}
+			return true
+		}
+	}
+	return false
+}
+
+type compressWriter struct {
+	http.ResponseWriter
+	gz          *gzip.Writer
+	wroteHeader bool
+}
+
+func (cw *compressWriter) WriteHeader(statusCode int) {
+	if cw.wroteHeader {
+		return
+	}
+	cw.wroteHeader = true
+
+	h := cw.Header()
+	if h.Get("Content-Encoding") == "" && bodyAllowed(statusCode) {
+		h.Set("Content-Encoding", "gzip")
+		h.Del("Content-Length")
+
+		cw.gz = gzipWriterPool.Get().(*gzip.Writer)
+		cw.gz.Reset(cw.ResponseWriter)
+	}
+
+	cw.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (cw *compressWriter) Write(data []byte) (int, error) {
+	if !cw.wroteHeader {
+		cw.WriteHeader(http.StatusOK)
+	}
+
+	if cw.gz == nil {
+		return cw.ResponseWriter.Write(data)
+	}
+
+	return cw.gz.Write(data)
+}
+
+// Close flushes the remaining compressed data and returns the gzip writer to
+// the pool.
+func (cw *compressWriter) Close() error {
+	if cw.gz == nil {
+		return nil
+	}
+
+	err := cw.gz.Close()
+	gzipWriterPool.Put(cw.gz)
+	cw.gz = nil
+
+	return err
+}
+
+func bodyAllowed(statusCode int) bool {
+	return statusCode >= http.StatusOK && statusCode != http.StatusNoContent && statusCode != http.StatusNotModified
+}
@@ -13,22 +13,25 @@ func ContentLengthMiddleware(next http.Handler) http.Handler {
 		clw := &contentLengthWriter{
 			ResponseWriter: w,
 			buffer:         &bytes.Buffer{},
+			statusCode:     http.StatusOK,
 		}
 
 		// Call the next handler with the wrapped ResponseWriter
 		next.ServeHTTP(clw, r)
 
-		// Set the Content-Length header
+		// Set the Content-Length header, the headers are only sent once the
+		// whole body is known
 		contentLength := clw.buffer.Len()
 		if clw.Header().Get("Content-Length") == "" {
 			clw.Header().Set("Content-Length", strconv.Itoa(contentLength))
 		}
 
-		// Write the buffered content to the original ResponseWriter
-		if !clw.wroteHeader {
-			clw.WriteHeader(clw.statusCode)
+		// Write the buffered content to the original ResponseWriter, a HEAD
+		// response only carries the headers of the would-be body
+		w.WriteHeader(clw.statusCode)
+		if r.Method != http.MethodHead {
+			w.Write(clw.buffer.Bytes())
 		}
-		w.Write(clw.buffer.Bytes())
 	})
 }
 
@@ -43,10 +46,10 @@ func (clw *contentLengthWriter) WriteHeader(statusCode int) {
 	if !clw.wroteHeader {
 		clw.statusCode = statusCode
 		clw.wroteHeader = true
-		clw.ResponseWriter.WriteHeader(statusCode)
 	}
 }
 
 func (clw *contentLengthWriter) Write(data []byte) (int, error) {
+	clw.wroteHeader = true
 	return clw.buffer.Write(data)
 }
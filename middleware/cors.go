@@ -13,6 +13,12 @@ type CORSOptions struct {
 	ExposedHeaders   []string
 	AllowCredentials bool
 	MaxAge           int
+
+	// AllowedMethodsFunc returns the methods registered for the requested path,
+	// typically Router.AllowedMethods. When set, preflight responses advertise
+	// these methods in both Access-Control-Allow-Methods and Allow, falling back
+	// to AllowedMethods when it returns none.
+	AllowedMethodsFunc func(*http.Request) []string
 }
 
 // CORS returns a Middleware that handles Cross-Origin Resource Sharing.
@@ -74,7 +80,14 @@ func CORS(options CORSOptions) func(http.Handler) http.Handler {
 				if options.AllowCredentials {
 					w.Header().Set("Access-Control-Allow-Credentials", "true")
 				}
-				if len(options.AllowedMethods) > 0 {
+				var routeMethods []string
+				if options.AllowedMethodsFunc != nil {
+					routeMethods = options.AllowedMethodsFunc(req)
+				}
+				if len(routeMethods) > 0 {
+					w.Header().Set("Access-Control-Allow-Methods", strings.Join(routeMethods, ", "))
+					w.Header().Set("Allow", strings.Join(routeMethods, ", "))
+				} else if len(options.AllowedMethods) > 0 {
 					w.Header().Set("Access-Control-Allow-Methods", strings.Join(options.AllowedMethods, ", "))
 				} else {
 					// Use the method from the request header
@@ -0,0 +1,51 @@
+package middleware
+
+import "net/http"
+
+// headerHookWriter calls hook with the status code and response header right
+// before they are written, so middlewares can alter headers set anywhere in the
+// handler.
+type headerHookWriter struct {
+	http.ResponseWriter
+	hook        func(status int, header http.Header)
+	wroteHeader bool
+}
+
+func (w *headerHookWriter) WriteHeader(statusCode int) {
+	if !w.wroteHeader {
+		w.hook(statusCode, w.ResponseWriter.Header())
+	}
+	if statusCode >= 200 {
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *headerHookWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *headerHookWriter) Flush() {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// finish runs the hook when the handler returned without writing, before
+// net/http sends the implicit 200 response with the header.
+func (w *headerHookWriter) finish() {
+	if !w.wroteHeader {
+		w.hook(http.StatusOK, w.ResponseWriter.Header())
+		w.wroteHeader = true
+	}
+}
+
+func (w *headerHookWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
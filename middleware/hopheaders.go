@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"net/http"
+	"net/textproto"
+	"strings"
+)
+
+// hopHeaders are the hop-by-hop headers defined in RFC 7230 section 6.1, which
+// are meaningful only for a single transport-level connection.
+var hopHeaders = []string{
+	"Connection",
+	"Proxy-Connection",
+	"Keep-Alive",
+	"Proxy-Authenticate",
+	"Proxy-Authorization",
+	"Te",
+	"Trailer",
+	"Transfer-Encoding",
+	"Upgrade",
+}
+
+// CleanHopHeaders returns a Middleware that removes hop-by-hop headers, and any
+// header listed in the Connection header, from the request before it reaches
+// the handler. This is useful in front of reverse-proxy handlers.
+func CleanHopHeaders() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			// Headers named in Connection are hop-by-hop as well
+			for _, value := range req.Header.Values("Connection") {
+				for _, name := range strings.Split(value, ",") {
+					if name = textproto.TrimString(name); name != "" {
+						req.Header.Del(name)
+					}
+				}
+			}
+
+			for _, name := range hopHeaders {
+				req.Header.Del(name)
+			}
+
+			next.ServeHTTP(w, req)
+		})
+	}
+}
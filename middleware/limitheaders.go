@@ -0,0 +1,28 @@
+package middleware
+
+import "net/http"
+
+// LimitHeaders returns a Middleware that rejects requests carrying more than
+// maxCount header fields, or whose header names and values add up to more than
+// maxTotalBytes, with 431 Request Header Fields Too Large. A limit of zero
+// disables the corresponding check.
+func LimitHeaders(maxCount int, maxTotalBytes int) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var count, size int
+			for name, values := range r.Header {
+				for _, value := range values {
+					count++
+					size += len(name) + len(value)
+				}
+			}
+
+			if (maxCount > 0 && count > maxCount) || (maxTotalBytes > 0 && size > maxTotalBytes) {
+				http.Error(w, http.StatusText(http.StatusRequestHeaderFieldsTooLarge), http.StatusRequestHeaderFieldsTooLarge)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
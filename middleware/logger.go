@@ -0,0 +1,102 @@
+package middleware
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// LoggerOptions configures Logger.
+type LoggerOptions struct {
+	Level slog.Level // Level of the request records, slog.LevelInfo by default
+}
+
+// Logger returns a Middleware that logs one record per request to the
+// structured logger, with the method, path, status code, response size in
+// bytes and duration as attributes, and the request ID when RequestID runs
+// before it.
+func Logger(logger *slog.Logger, options ...LoggerOptions) func(http.Handler) http.Handler {
+	var opts LoggerOptions
+	if len(options) > 0 {
+		opts = options[0]
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			t := time.Now()
+			sw := NewStatusWriter(w)
+
+			next.ServeHTTP(sw, r)
+
+			attrs := []slog.Attr{
+				slog.String("method", r.Method),
+				slog.String("path", r.URL.Path),
+				slog.Int("status", sw.Status()),
+				slog.Int("bytes", sw.Bytes()),
+				slog.Duration("duration", time.Since(t)),
+			}
+			if id := RequestIDFromContext(r.Context()); id != "" {
+				attrs = append(attrs, slog.String("request_id", id))
+			}
+
+			logger.LogAttrs(context.WithoutCancel(r.Context()), opts.Level, "request", attrs...)
+		})
+	}
+}
+
+// StatusWriter is a http.ResponseWriter recording the status code and number
+// of bytes of the response, for middlewares reporting on it.
+type StatusWriter struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+// NewStatusWriter returns a StatusWriter writing to w.
+func NewStatusWriter(w http.ResponseWriter) *StatusWriter {
+	return &StatusWriter{ResponseWriter: w}
+}
+
+// Status returns the status code of the response, 200 when the handler wrote
+// nothing.
+func (w *StatusWriter) Status() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+	return w.status
+}
+
+// Bytes returns the number of bytes of the response body written so far.
+func (w *StatusWriter) Bytes() int {
+	return w.bytes
+}
+
+func (w *StatusWriter) WriteHeader(statusCode int) {
+	if w.status == 0 && statusCode >= 200 {
+		w.status = statusCode
+	}
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *StatusWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += n
+	return n, err
+}
+
+func (w *StatusWriter) Flush() {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (w *StatusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
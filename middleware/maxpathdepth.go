@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+)
+
+// MaxPathDepth returns a Middleware that rejects requests whose path has more
+// than n non-empty segments with 400 Bad Request, e.g. to guard wildcard and
+// file serving routes against deeply nested paths.
+func MaxPathDepth(n int) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var depth int
+			for _, segment := range strings.Split(r.URL.Path, "/") {
+				if segment != "" {
+					depth++
+				}
+			}
+
+			if depth > n {
+				http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package middleware
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// NonceStore records the nonces seen by RequireNonce. Implementations must be
+// safe for concurrent use.
+type NonceStore interface {
+	// Use records the nonce and reports whether it had not been seen before.
+	Use(nonce string) bool
+}
+
+// MemoryNonceStore is an in-memory NonceStore that remembers nonces for a fixed
+// time to live.
+type MemoryNonceStore struct {
+	mu        sync.Mutex
+	ttl       time.Duration
+	seen      map[string]time.Time
+	lastSweep time.Time
+}
+
+// NewMemoryNonceStore returns a MemoryNonceStore that forgets nonces once the
+// ttl has passed, after which they are accepted again.
+func NewMemoryNonceStore(ttl time.Duration) *MemoryNonceStore {
+	return &MemoryNonceStore{
+		ttl:  ttl,
+		seen: make(map[string]time.Time),
+	}
+}
+
+// Use implements NonceStore.
+func (s *MemoryNonceStore) Use(nonce string) bool {
+	return s.use(nonce, time.Now())
+}
+
+func (s *MemoryNonceStore) use(nonce string, now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweep(now)
+
+	if expires, ok := s.seen[nonce]; ok && now.Before(expires) {
+		return false
+	}
+
+	s.seen[nonce] = now.Add(s.ttl)
+	return true
+}
+
+// sweep drops the expired nonces, at most once per ttl.
+func (s *MemoryNonceStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < s.ttl {
+		return
+	}
+	s.lastSweep = now
+
+	for nonce, expires := range s.seen {
+		if !now.Before(expires) {
+			delete(s.seen, nonce)
+		}
+	}
+}
+
+// RequireNonce returns a Middleware that protects against replayed requests by
+// requiring a unique nonce in the given header. Requests without the header
+// are rejected with 400 Bad Request, requests reusing a nonce known to the
+// store with 409 Conflict. Unlike idempotency keys, duplicates are never
+// answered with the original response.
+func RequireNonce(store NonceStore, header string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			nonce := r.Header.Get(header)
+			if nonce == "" {
+				http.Error(w, "missing "+header+" header", http.StatusBadRequest)
+				return
+			}
+
+			if !store.Use(nonce) {
+				http.Error(w, "nonce already used", http.StatusConflict)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMemoryNonceStoreExpiry(t *testing.T) {
+	s := NewMemoryNonceStore(time.Minute)
+	start := time.Now()
+
+	if !s.use("a", start) {
+		t.Fatal("Expected the first use of a to be allowed")
+	}
+	if s.use("a", start.Add(59*time.Second)) {
+		t.Error("Expected a replay of a within the ttl to be rejected")
+	}
+	if !s.use("b", start.Add(30*time.Second)) {
+		t.Fatal("Expected the first use of b to be allowed")
+	}
+
+	// a expired, b is still remembered
+	if !s.use("a", start.Add(time.Minute)) {
+		t.Error("Expected a to be accepted again once the ttl has passed")
+	}
+	if s.use("b", start.Add(time.Minute)) {
+		t.Error("Expected a replay of b within its ttl to be rejected")
+	}
+
+	// the sweep drops the expired nonces
+	s.use("c", start.Add(3*time.Minute))
+	if len(s.seen) != 1 {
+		t.Errorf("Expected only c to be kept after the sweep, got %d nonces", len(s.seen))
+	}
+}
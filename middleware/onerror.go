@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"net/http"
+)
+
+// OnError returns a Middleware that calls fn when the handler responds with an
+// error status, 400 or above. fn is called right before the response header is
+// written, so it may still add headers, e.g. a support ID on 5xx responses:
+//
+//	r.Use(middleware.OnError(func(w http.ResponseWriter, r *http.Request, status int) {
+//		if status >= 500 {
+//			w.Header().Set("X-Support-ID", supportID(r))
+//		}
+//	}))
+func OnError(fn func(w http.ResponseWriter, r *http.Request, status int)) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			hw := &headerHookWriter{ResponseWriter: w, hook: func(status int, _ http.Header) {
+				if status >= http.StatusBadRequest {
+					fn(w, r, status)
+				}
+			}}
+
+			next.ServeHTTP(hw, r)
+			hw.finish()
+		})
+	}
+}
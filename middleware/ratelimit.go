@@ -0,0 +1,119 @@
+package middleware
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RateLimitOptions configures RateLimit.
+type RateLimitOptions struct {
+	Rate  float64                    // Requests allowed per second on average, the refill rate of the bucket, must be positive
+	Burst int                        // Requests allowed at once, the capacity of the bucket, at least 1
+	Key   func(*http.Request) string // Key of the bucket of a request, the client IP when nil
+}
+
+// RateLimit returns a Middleware limiting the requests per key with a token
+// bucket: every key may send Burst requests at once, refilled at Rate requests
+// per second. Requests finding the bucket empty are rejected with 429 Too Many
+// Requests and a Retry-After header with the seconds until a token is
+// available. It panics when Rate is not positive or Burst is below 1, as the
+// bucket could then never hold a token.
+//
+// Every response carries the X-RateLimit-Limit and X-RateLimit-Remaining
+// headers. Buckets that refilled completely are forgotten, so memory only
+// grows with the number of recently active keys.
+func RateLimit(options RateLimitOptions) func(http.Handler) http.Handler {
+	if options.Rate <= 0 {
+		panic(fmt.Sprintf("middleware: RateLimit needs a positive Rate, got %v", options.Rate))
+	}
+	if options.Burst < 1 {
+		panic(fmt.Sprintf("middleware: RateLimit needs a Burst of at least 1, got %d", options.Burst))
+	}
+
+	keyFn := options.Key
+	if keyFn == nil {
+		keyFn = clientIP
+	}
+
+	tb := &tokenBuckets{
+		rate:    options.Rate,
+		burst:   float64(options.Burst),
+		buckets: make(map[string]*tokenBucket),
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			remaining, retryAfter, ok := tb.take(keyFn(r), time.Now())
+
+			w.Header().Set("X-RateLimit-Limit", strconv.Itoa(int(tb.burst)))
+			w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+
+			if !ok {
+				w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+				http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+type tokenBuckets struct {
+	mu        sync.Mutex
+	rate      float64
+	burst     float64
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// refill adds the tokens accumulated since the last update, up to the burst.
+func (tb *tokenBuckets) refill(b *tokenBucket, now time.Time) {
+	b.tokens = math.Min(tb.burst, b.tokens+now.Sub(b.last).Seconds()*tb.rate)
+	b.last = now
+}
+
+func (tb *tokenBuckets) take(key string, now time.Time) (remaining int, retryAfter time.Duration, ok bool) {
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+
+	tb.sweep(now)
+
+	b, exists := tb.buckets[key]
+	if !exists {
+		b = &tokenBucket{tokens: tb.burst, last: now}
+		tb.buckets[key] = b
+	}
+	tb.refill(b, now)
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return int(b.tokens), 0, true
+	}
+	return 0, time.Duration((1 - b.tokens) / tb.rate * float64(time.Second)), false
+}
+
+// sweep drops the buckets that refilled completely, at most once per the time
+// an empty bucket takes to refill.
+func (tb *tokenBuckets) sweep(now time.Time) {
+	interval := time.Duration(tb.burst / tb.rate * float64(time.Second))
+	if now.Sub(tb.lastSweep) < interval {
+		return
+	}
+	tb.lastSweep = now
+
+	for key, b := range tb.buckets {
+		if tb.refill(b, now); b.tokens >= tb.burst {
+			delete(tb.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTokenBucketsSweep(t *testing.T) {
+	tb := &tokenBuckets{
+		rate:    1,
+		burst:   2,
+		buckets: make(map[string]*tokenBucket),
+	}
+	start := time.Now()
+
+	for _, key := range []string{"a", "b", "c"} {
+		if _, _, ok := tb.take(key, start); !ok {
+			t.Fatalf("Expected the first request of %s to be allowed", key)
+		}
+	}
+	if len(tb.buckets) != 3 {
+		t.Fatalf("Expected 3 buckets, got %d", len(tb.buckets))
+	}
+
+	// b drains its bucket, refilled after 2s
+	if _, _, ok := tb.take("b", start.Add(time.Second)); !ok {
+		t.Fatal("Expected the second request of b to be allowed")
+	}
+	if _, _, ok := tb.take("b", start.Add(time.Second)); !ok {
+		t.Fatal("Expected the third request of b to be allowed")
+	}
+	if _, retryAfter, ok := tb.take("b", start.Add(time.Second)); ok || retryAfter != time.Second {
+		t.Fatalf("Expected b to be rejected for 1s, got allowed %v and retry after %v", ok, retryAfter)
+	}
+
+	// a and c refilled completely, b is still refilling
+	if _, _, ok := tb.take("d", start.Add(2*time.Second)); !ok {
+		t.Fatal("Expected the first request of d to be allowed")
+	}
+	for key, want := range map[string]bool{"a": false, "b": true, "c": false, "d": true} {
+		if _, ok := tb.buckets[key]; ok != want {
+			t.Errorf("Expected bucket %s kept %v after the sweep, got %v", key, want, ok)
+		}
+	}
+
+	// a forgotten bucket starts full again
+	if remaining, _, ok := tb.take("a", start.Add(2*time.Second)); !ok || remaining != 1 {
+		t.Errorf("Expected a full bucket for a, got allowed %v with %d remaining", ok, remaining)
+	}
+
+	// the next sweep runs once an empty bucket had the time to refill
+	if _, _, ok := tb.take("d", start.Add(5*time.Second)); !ok {
+		t.Fatal("Expected the second request of d to be allowed")
+	}
+	if len(tb.buckets) != 1 {
+		t.Errorf("Expected only the bucket of d to be kept, got %d buckets", len(tb.buckets))
+	}
+}
@@ -1,21 +1,71 @@
 package middleware
 
 import (
+	"fmt"
+	"io"
 	"log"
 	"net/http"
 )
 
-func Recover(next http.Handler) http.Handler {
-	fn := func(w http.ResponseWriter, r *http.Request) {
-		defer func() {
-			if err := recover(); err != nil {
-				log.Printf("[go-router] %s", err)
-				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
-			}
-		}()
-
-		next.ServeHTTP(w, r)
+// RecoverOptions configures RecoverWithOptions.
+type RecoverOptions struct {
+	Output io.Writer        // Destination of the panic lines, the standard logger when nil
+	Report func(PanicEntry) // Called with every recovered panic, e.g. to notify an error tracker
+}
+
+// PanicEntry describes a recovered panic and the request that caused it.
+type PanicEntry struct {
+	Value     any    // Value passed to panic
+	Method    string // Method of the request
+	Path      string // Path of the request
+	RequestID string // ID set by the RequestID middleware, empty without it
+}
+
+func (e PanicEntry) String() string {
+	s := fmt.Sprintf("[go-router] panic serving %s %s: %v", e.Method, e.Path, e.Value)
+	if e.RequestID != "" {
+		s += " (request_id=" + e.RequestID + ")"
 	}
+	return s
+}
+
+var defaultRecover = RecoverWithOptions(RecoverOptions{})
 
-	return http.HandlerFunc(fn)
+// Recover recovers panics of the handler, logs them with the method, path and
+// request ID of the request to the standard logger and responds with 500
+// Internal Server Error.
+func Recover(next http.Handler) http.Handler {
+	return defaultRecover(next)
+}
+
+// RecoverWithOptions returns a Middleware like Recover that logs to the given
+// writer and reports the panics to a callback.
+func RecoverWithOptions(options RecoverOptions) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				if err := recover(); err != nil {
+					entry := PanicEntry{
+						Value:     err,
+						Method:    r.Method,
+						Path:      r.URL.Path,
+						RequestID: RequestIDFromContext(r.Context()),
+					}
+
+					if options.Output == nil {
+						log.Print(entry)
+					} else {
+						_, _ = io.WriteString(options.Output, entry.String()+"\n")
+					}
+					if options.Report != nil {
+						options.Report(entry)
+					}
+
+					http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+				}
+			}()
+
+			next.ServeHTTP(w, r)
+		})
+	}
 }
@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"net/http"
+)
+
+// RequestIDHeader is the header RequestID reads and echoes the request ID in.
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength bounds the length of incoming request IDs, longer ones
+// are replaced by a generated ID.
+const maxRequestIDLength = 128
+
+// GenerateRequestID returns the ID of requests arriving without one, a random
+// UUID by default. It may be replaced, e.g. for deterministic tests.
+var GenerateRequestID = newUUID
+
+type requestIDKey struct{}
+
+// RequestID stores the ID of every request in its context and echoes it in the
+// X-Request-ID response header. The ID is read from the X-Request-ID request
+// header, or generated with GenerateRequestID when absent or invalid.
+func RequestID(next http.Handler) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if !validRequestID(id) {
+			id = GenerateRequestID()
+		}
+
+		w.Header().Set(RequestIDHeader, id)
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), requestIDKey{}, id)))
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+// RequestIDFromContext returns the request ID stored by RequestID, or an empty
+// string when there is none.
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// validRequestID reports whether an incoming ID is non-empty, bounded and made
+// of visible ASCII characters only, so it is safe to echo and log.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// newUUID returns a random version 4 UUID.
+func newUUID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
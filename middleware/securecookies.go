@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"net/http"
+)
+
+// CookieDefaults are the attributes enforced by SecureCookies.
+type CookieDefaults struct {
+	HttpOnly bool          // Hide cookies from scripts
+	SameSite http.SameSite // Applied to cookies without a SameSite attribute
+	Secure   bool          // Restrict cookies to HTTPS, only applied to HTTPS requests
+}
+
+// SecureCookies returns a Middleware that rewrites the Set-Cookie headers of the
+// response to carry the attributes of defaults. Attributes set by the handler
+// are kept; Secure is only added when the request was made over HTTPS, so
+// cookies keep working on plain HTTP during development.
+func SecureCookies(defaults CookieDefaults) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			secure := defaults.Secure && r.TLS != nil
+
+			hw := &headerHookWriter{ResponseWriter: w, hook: func(_ int, header http.Header) {
+				values := header.Values("Set-Cookie")
+				rewritten := make([]string, len(values))
+				for i, value := range values {
+					rewritten[i] = value
+
+					cookie, err := http.ParseSetCookie(value)
+					if err != nil {
+						continue // leave cookies we cannot parse untouched
+					}
+
+					if defaults.HttpOnly {
+						cookie.HttpOnly = true
+					}
+					if cookie.SameSite == 0 {
+						cookie.SameSite = defaults.SameSite
+					}
+					if secure {
+						cookie.Secure = true
+					}
+
+					if s := cookie.String(); s != "" {
+						rewritten[i] = s
+					}
+				}
+				if len(rewritten) > 0 {
+					header["Set-Cookie"] = rewritten
+				}
+			}}
+
+			next.ServeHTTP(hw, r)
+			hw.finish()
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// SequenceStore records the last sequence number seen per session by
+// SequenceGuard. Implementations must be safe for concurrent use.
+type SequenceStore interface {
+	// Advance records seq for the session and reports whether it is greater
+	// than the last sequence number recorded for it.
+	Advance(session string, seq uint64) bool
+}
+
+// MemorySequenceStore is an in-memory SequenceStore that forgets sessions left
+// idle for a fixed time to live.
+type MemorySequenceStore struct {
+	mu        sync.Mutex
+	ttl       time.Duration
+	sessions  map[string]sequenceEntry
+	lastSweep time.Time
+}
+
+type sequenceEntry struct {
+	seq     uint64
+	expires time.Time
+}
+
+// NewMemorySequenceStore returns a MemorySequenceStore that forgets a session
+// once no request advanced it for the ttl, after which any sequence number is
+// accepted again.
+func NewMemorySequenceStore(ttl time.Duration) *MemorySequenceStore {
+	return &MemorySequenceStore{
+		ttl:      ttl,
+		sessions: make(map[string]sequenceEntry),
+	}
+}
+
+// Advance implements SequenceStore.
+func (s *MemorySequenceStore) Advance(session string, seq uint64) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.sweep(now)
+
+	if entry, ok := s.sessions[session]; ok && now.Before(entry.expires) && seq <= entry.seq {
+		return false
+	}
+
+	s.sessions[session] = sequenceEntry{seq: seq, expires: now.Add(s.ttl)}
+	return true
+}
+
+// sweep drops the expired sessions, at most once per ttl.
+func (s *MemorySequenceStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < s.ttl {
+		return
+	}
+	s.lastSweep = now
+
+	for session, entry := range s.sessions {
+		if !now.Before(entry.expires) {
+			delete(s.sessions, session)
+		}
+	}
+}
+
+// SequenceOptions configures SequenceGuard.
+type SequenceOptions struct {
+	// SessionKey returns the session of a request. By default requests are
+	// grouped by their Authorization header, or by client address without one.
+	SessionKey func(*http.Request) string
+}
+
+// SequenceGuard returns a Middleware that rejects out of order requests within
+// a session, using the monotonically increasing sequence number sent in the
+// given header. Requests without a valid sequence number are rejected with 400
+// Bad Request, requests whose sequence number is not greater than the last one
+// of their session with 409 Conflict.
+func SequenceGuard(store SequenceStore, header string, options ...SequenceOptions) func(http.Handler) http.Handler {
+	sessionKey := defaultSessionKey
+	if len(options) > 0 && options[0].SessionKey != nil {
+		sessionKey = options[0].SessionKey
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			seq, err := strconv.ParseUint(r.Header.Get(header), 10, 64)
+			if err != nil {
+				http.Error(w, "missing or invalid "+header+" header", http.StatusBadRequest)
+				return
+			}
+
+			if !store.Advance(sessionKey(r), seq) {
+				http.Error(w, "request out of sequence", http.StatusConflict)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func defaultSessionKey(r *http.Request) string {
+	if auth := r.Header.Get("Authorization"); auth != "" {
+		return auth
+	}
+	return clientIP(r)
+}
@@ -0,0 +1,122 @@
+package middleware
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// SlidingWindow returns a Middleware that allows at most limit requests per key
+// within any rolling window. Requests over the limit are rejected with 429 Too
+// Many Requests. The key defaults to the client IP when keyFn is nil.
+//
+// Every response carries the X-RateLimit-Limit, X-RateLimit-Remaining and
+// X-RateLimit-Reset headers, the latter being the number of seconds until a
+// request slot frees up.
+func SlidingWindow(limit int, window time.Duration, keyFn func(*http.Request) string) func(http.Handler) http.Handler {
+	if keyFn == nil {
+		keyFn = clientIP
+	}
+
+	sw := &slidingWindow{
+		limit:   max(limit, 0),
+		window:  window,
+		entries: make(map[string]*windowLog),
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			remaining, reset, ok := sw.allow(keyFn(r), time.Now())
+
+			resetSeconds := strconv.Itoa(int(math.Ceil(reset.Seconds())))
+			w.Header().Set("X-RateLimit-Limit", strconv.Itoa(sw.limit))
+			w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+			w.Header().Set("X-RateLimit-Reset", resetSeconds)
+
+			if !ok {
+				w.Header().Set("Retry-After", resetSeconds)
+				http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+type slidingWindow struct {
+	mu        sync.Mutex
+	limit     int
+	window    time.Duration
+	entries   map[string]*windowLog
+	lastSweep time.Time
+}
+
+// windowLog is a ring buffer holding the timestamps of the requests within the
+// current window, so memory per key is bounded by the limit.
+type windowLog struct {
+	times []time.Time
+	head  int
+	size  int
+}
+
+func (l *windowLog) evict(now time.Time, window time.Duration) {
+	for l.size > 0 && now.Sub(l.times[l.head]) >= window {
+		l.head = (l.head + 1) % len(l.times)
+		l.size--
+	}
+}
+
+func (sw *slidingWindow) allow(key string, now time.Time) (remaining int, reset time.Duration, ok bool) {
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+
+	sw.sweep(now)
+
+	if sw.limit == 0 {
+		return 0, sw.window, false
+	}
+
+	l, exists := sw.entries[key]
+	if !exists {
+		l = &windowLog{times: make([]time.Time, sw.limit)}
+		sw.entries[key] = l
+	}
+
+	l.evict(now, sw.window)
+
+	if l.size < sw.limit {
+		l.times[(l.head+l.size)%sw.limit] = now
+		l.size++
+		ok = true
+	}
+
+	return sw.limit - l.size, l.times[l.head].Add(sw.window).Sub(now), ok
+}
+
+// sweep drops the keys without requests in the current window, at most once
+// per window.
+func (sw *slidingWindow) sweep(now time.Time) {
+	if now.Sub(sw.lastSweep) < sw.window {
+		return
+	}
+	sw.lastSweep = now
+
+	for key, l := range sw.entries {
+		if l.evict(now, sw.window); l.size == 0 {
+			delete(sw.entries, key)
+		}
+	}
+}
+
+// clientIP returns the IP address of the client, without the port.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"net/http"
+)
+
+// StripResponseHeaders returns a Middleware that removes the named headers from
+// the response right before it is written, e.g. Server or X-Powered-By headers
+// leaked by handlers or proxied services.
+func StripResponseHeaders(names ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			hw := &headerHookWriter{ResponseWriter: w, hook: func(_ int, header http.Header) {
+				for _, name := range names {
+					header.Del(name)
+				}
+			}}
+
+			next.ServeHTTP(hw, r)
+			hw.finish()
+		})
+	}
+}
@@ -1,19 +1,115 @@
 package middleware
 
 import (
+	"bytes"
+	"io"
 	"log"
+	"log/slog"
 	"net/http"
+	"strings"
+	"text/template"
 	"time"
 )
 
+// TimerOptions configures TimerWithOptions.
+type TimerOptions struct {
+	Logger        *slog.Logger // Structured logger, takes precedence over Output and Format
+	Output        io.Writer    // Destination of the formatted lines, the standard logger when nil
+	Format        string       // text/template executed with a TimerEntry, DefaultTimerFormat when empty
+	IncludeStatus bool         // Record the status code and bytes written
+}
+
+// TimerEntry holds the details of a timed request, as passed to the format
+// template.
+type TimerEntry struct {
+	Duration time.Duration
+	Method   string
+	Path     string
+	Status   int // Only recorded with TimerOptions.IncludeStatus
+	Bytes    int // Only recorded with TimerOptions.IncludeStatus
+}
+
+const (
+	// DefaultTimerFormat is the format used by Timer.
+	DefaultTimerFormat = `[go-router] {{printf "%-10s %-7s %s" .Duration .Method .Path}}`
+	// DefaultTimerStatusFormat is the default format when the status is included.
+	DefaultTimerStatusFormat = `[go-router] {{printf "%-10s %-7s %s %d %dB" .Duration .Method .Path .Status .Bytes}}`
+)
+
+var defaultTimer = TimerWithOptions(TimerOptions{})
+
+// Timer logs the duration, method and path of every request to the standard
+// logger, using DefaultTimerFormat.
 func Timer(next http.Handler) http.Handler {
-	fn := func(w http.ResponseWriter, r *http.Request) {
-		t := time.Now()
+	return defaultTimer(next)
+}
 
-		next.ServeHTTP(w, r)
+// TimerWithOptions returns a Middleware like Timer that logs to a structured
+// logger or writer, using a custom format. It panics when the format is not a
+// valid template.
+func TimerWithOptions(options TimerOptions) func(http.Handler) http.Handler {
+	format := options.Format
+	if format == "" {
+		format = DefaultTimerFormat
+		if options.IncludeStatus {
+			format = DefaultTimerStatusFormat
+		}
+	}
+	tmpl := template.Must(template.New("timer").Parse(format))
+
+	logEntry := func(entry TimerEntry) {
+		if options.Logger != nil {
+			attrs := []any{
+				slog.String("method", entry.Method),
+				slog.String("path", entry.Path),
+				slog.Duration("duration", entry.Duration),
+			}
+			if options.IncludeStatus {
+				attrs = append(attrs, slog.Int("status", entry.Status), slog.Int("bytes", entry.Bytes))
+			}
+			options.Logger.Info("request", attrs...)
+			return
+		}
 
-		log.Printf("[go-router] %-10s %-7s %s", time.Since(t), r.Method, r.URL.Path)
+		var line bytes.Buffer
+		if err := tmpl.Execute(&line, entry); err != nil {
+			log.Printf("[go-router] timer format: %v", err)
+			return
+		}
+
+		if options.Output == nil {
+			log.Print(line.String())
+			return
+		}
+		if !strings.HasSuffix(line.String(), "\n") {
+			line.WriteByte('\n')
+		}
+		_, _ = options.Output.Write(line.Bytes())
 	}
 
-	return http.HandlerFunc(fn)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			t := time.Now()
+
+			var sw *StatusWriter
+			if options.IncludeStatus {
+				sw = NewStatusWriter(w)
+				w = sw
+			}
+
+			next.ServeHTTP(w, r)
+
+			entry := TimerEntry{
+				Duration: time.Since(t),
+				Method:   r.Method,
+				Path:     r.URL.Path,
+			}
+			if sw != nil {
+				entry.Status = sw.Status()
+				entry.Bytes = sw.Bytes()
+			}
+
+			logEntry(entry)
+		})
+	}
 }
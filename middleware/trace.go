@@ -0,0 +1,181 @@
+package middleware
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// TraceFormat selects the trace propagation headers.
+type TraceFormat int
+
+const (
+	TraceW3C TraceFormat = iota // W3C Trace Context traceparent header
+	TraceB3                     // Zipkin B3 headers, single or multi header
+)
+
+// TraceContext holds the identifiers of the span serving the current request.
+type TraceContext struct {
+	TraceID      string // Identifier of the whole trace
+	SpanID       string // Identifier of the span serving this request
+	ParentSpanID string // Span of the caller, empty when the trace started here
+	Sampled      bool   // Whether the trace is sampled
+}
+
+type traceContextKey struct{}
+
+// PropagateTrace returns a Middleware that reads the trace context of the
+// incoming request in the given format and stores a child span in the request
+// context. A new trace is started when the headers are absent or invalid.
+func PropagateTrace(format TraceFormat) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var (
+				parent TraceContext
+				ok     bool
+			)
+
+			switch format {
+			case TraceB3:
+				parent, ok = parseB3(r.Header)
+			default:
+				parent, ok = parseTraceparent(r.Header.Get("traceparent"))
+			}
+
+			tc := TraceContext{
+				TraceID: parent.TraceID,
+				SpanID:  randomHex(8),
+				Sampled: true,
+			}
+			if ok {
+				tc.ParentSpanID = parent.SpanID
+				tc.Sampled = parent.Sampled
+			} else {
+				tc.TraceID = randomHex(16)
+			}
+
+			ctx := context.WithValue(r.Context(), traceContextKey{}, traceState{tc: tc, format: format})
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+type traceState struct {
+	tc     TraceContext
+	format TraceFormat
+}
+
+// TraceFromContext returns the trace context stored by PropagateTrace.
+func TraceFromContext(ctx context.Context) (TraceContext, bool) {
+	state, ok := ctx.Value(traceContextKey{}).(traceState)
+	return state.tc, ok
+}
+
+// InjectTrace sets the trace headers on an outgoing request so the downstream
+// service continues the trace of ctx, using the format of PropagateTrace.
+func InjectTrace(ctx context.Context, req *http.Request) {
+	state, ok := ctx.Value(traceContextKey{}).(traceState)
+	if !ok {
+		return
+	}
+
+	tc := state.tc
+	switch state.format {
+	case TraceB3:
+		req.Header.Set("X-B3-TraceId", tc.TraceID)
+		req.Header.Set("X-B3-SpanId", tc.SpanID)
+		if tc.Sampled {
+			req.Header.Set("X-B3-Sampled", "1")
+		} else {
+			req.Header.Set("X-B3-Sampled", "0")
+		}
+	default:
+		flags := "00"
+		if tc.Sampled {
+			flags = "01"
+		}
+		req.Header.Set("traceparent", fmt.Sprintf("00-%s-%s-%s", tc.TraceID, tc.SpanID, flags))
+	}
+}
+
+// parseTraceparent parses a W3C traceparent header,
+// e.g. 00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01.
+func parseTraceparent(header string) (TraceContext, bool) {
+	parts := strings.Split(strings.TrimSpace(header), "-")
+	if len(parts) < 4 {
+		return TraceContext{}, false
+	}
+
+	version, traceID, spanID, flags := parts[0], parts[1], parts[2], parts[3]
+	if !isHex(version, 2) || version == "ff" || (version == "00" && len(parts) != 4) {
+		return TraceContext{}, false
+	}
+	if !isHex(traceID, 32) || !isHex(spanID, 16) || !isHex(flags, 2) || isZero(traceID) || isZero(spanID) {
+		return TraceContext{}, false
+	}
+
+	flagBits, _ := hex.DecodeString(flags)
+	return TraceContext{
+		TraceID: traceID,
+		SpanID:  spanID,
+		Sampled: flagBits[0]&1 == 1,
+	}, true
+}
+
+// parseB3 parses the single b3 header or the X-B3-* multi headers.
+func parseB3(h http.Header) (TraceContext, bool) {
+	var traceID, spanID, sampled string
+	if single := h.Get("b3"); single != "" {
+		parts := strings.Split(single, "-")
+		if len(parts) < 2 {
+			return TraceContext{}, false
+		}
+		traceID, spanID = parts[0], parts[1]
+		if len(parts) > 2 {
+			sampled = parts[2]
+		}
+	} else {
+		traceID = h.Get("X-B3-TraceId")
+		spanID = h.Get("X-B3-SpanId")
+		sampled = h.Get("X-B3-Sampled")
+		if h.Get("X-B3-Flags") == "1" {
+			sampled = "d"
+		}
+	}
+
+	traceID, spanID = strings.ToLower(traceID), strings.ToLower(spanID)
+	if !(isHex(traceID, 16) || isHex(traceID, 32)) || !isHex(spanID, 16) || isZero(traceID) || isZero(spanID) {
+		return TraceContext{}, false
+	}
+
+	return TraceContext{
+		TraceID: traceID,
+		SpanID:  spanID,
+		Sampled: sampled == "" || sampled == "1" || sampled == "d" || sampled == "true",
+	}, true
+}
+
+func isHex(s string, length int) bool {
+	if len(s) != length {
+		return false
+	}
+	for _, c := range s {
+		if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+func isZero(s string) bool {
+	return strings.Trim(s, "0") == ""
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,48 @@
+package router
+
+import "strings"
+
+// NamingStrategy controls how operation IDs are derived from the method and
+// path of routes documented without an explicit OperationID. The zero value
+// renders GET /users/{id} as GETUsersId.
+type NamingStrategy struct {
+	LowerMethod bool   // Render the method in lower case, e.g. getUsersId
+	ParamPrefix string // Prepended to path parameters, e.g. "By" renders {id} as ById
+	SkipParams  bool   // Leave path parameters out of the operation ID
+}
+
+// UseNamingStrategy sets the strategy used to derive operation IDs for the
+// routes registered afterwards.
+func (r *Router) UseNamingStrategy(strategy NamingStrategy) {
+	r.namingStrategy = strategy
+}
+
+// operationID renders the operation ID of the method and documented path.
+func (n NamingStrategy) operationID(method, pattern string) string {
+	if n.LowerMethod {
+		method = strings.ToLower(method)
+	}
+
+	if pattern == "" || pattern == "/" {
+		return method + "Root"
+	}
+
+	var b strings.Builder
+	b.WriteString(method)
+	for _, part := range strings.Split(pattern, "/") {
+		if part == "" {
+			continue
+		}
+
+		if strings.HasPrefix(part, "{") {
+			if n.SkipParams {
+				continue
+			}
+			b.WriteString(n.ParamPrefix)
+			part = strings.TrimRight(strings.TrimLeft(part, "{"), "}")
+		}
+		b.WriteString(strings.Title(part))
+	}
+
+	return b.String()
+}
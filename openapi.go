@@ -4,20 +4,24 @@ import "net/http"
 
 // OpenAPI represents the root OpenAPI document.
 type OpenAPI struct {
-	Openapi    string                `json:"openapi" validate:"required"` // OpenAPI version (e.g., "3.0.1")
-	Info       Info                  `json:"info" validate:"required"`    // API information
-	Servers    []Server              `json:"servers,omitempty"`           // Server details
-	Paths      map[string]PathItem   `json:"paths" validate:"required"`   // Paths and operations
-	Components Components            `json:"components,omitempty"`        // Components such as schemas and security schemes
-	Security   []map[string][]string `json:"security,omitempty"`          // Global security settings
-	Tags       []Tag                 `json:"tags,omitempty"`              // Tags for API organization
+	Openapi           string                `json:"openapi" validate:"required"` // OpenAPI version (e.g., "3.0.1")
+	Info              Info                  `json:"info" validate:"required"`    // API information
+	JSONSchemaDialect string                `json:"jsonSchemaDialect,omitempty"` // Default $schema of the schemas, OpenAPI 3.1 and later only
+	Servers           []Server              `json:"servers,omitempty"`           // Server details
+	Paths             map[string]PathItem   `json:"paths" validate:"required"`   // Paths and operations
+	Components        Components            `json:"components,omitempty"`        // Components such as schemas and security schemes
+	Security          []map[string][]string `json:"security,omitempty"`          // Global security settings
+	Tags              []Tag                 `json:"tags,omitempty"`              // Tags for API organization
+	Extensions        map[string]any        `json:"-"`                           // Vendor extensions (x-*)
 }
 
 // Info represents the API metadata.
 type Info struct {
-	Title       string `json:"title" validate:"required"`   // API title
-	Description string `json:"description,omitempty"`       // API description
-	Version     string `json:"version" validate:"required"` // API version
+	Title       string         `json:"title" validate:"required"`   // API title
+	Summary     string         `json:"summary,omitempty"`           // Short API summary, OpenAPI 3.1 and later only
+	Description string         `json:"description,omitempty"`       // API description, may contain markdown
+	Version     string         `json:"version" validate:"required"` // API version
+	Extensions  map[string]any `json:"-"`                           // Vendor extensions (x-*)
 }
 
 // Server represents an API server.
@@ -73,16 +77,37 @@ func (p PathItem) SetMethod(method string, operation *Operation) PathItem {
 	return p
 }
 
+// GetMethod returns the operation registered for the given method, or nil.
+func (p PathItem) GetMethod(method string) *Operation {
+	switch method {
+	case http.MethodGet:
+		return p.Get
+	case http.MethodPost:
+		return p.Post
+	case http.MethodPut:
+		return p.Put
+	case http.MethodDelete:
+		return p.Delete
+	case http.MethodPatch:
+		return p.Patch
+	}
+
+	return nil
+}
+
 // Operation describes a single API operation on a path.
 type Operation struct {
-	Tags        []string              `json:"tags,omitempty"`                // Tags for the operation
-	Summary     string                `json:"summary,omitempty"`             // Short summary of the operation
-	Description string                `json:"description,omitempty"`         // Operation description
-	OperationID string                `json:"operationId,omitempty"`         // Unique operation ID
-	Parameters  []Parameter           `json:"parameters,omitempty"`          // Parameters for the operation
-	RequestBody *RequestBody          `json:"requestBody,omitempty"`         // Request body for the operation
-	Responses   map[string]Response   `json:"responses" validate:"required"` // Expected responses
-	Security    []map[string][]string `json:"security,omitempty"`            // Security requirements
+	Tags        []string              `json:"tags,omitempty"`                      // Tags for the operation
+	Summary     string                `json:"summary,omitempty"`                   // Short summary of the operation
+	Description string                `json:"description,omitempty"`               // Operation description
+	OperationID string                `json:"operationId,omitempty"`               // Unique operation ID
+	Parameters  []Parameter           `json:"parameters,omitempty"`                // Parameters for the operation
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`               // Request body for the operation
+	Responses   map[string]Response   `json:"responses" validate:"required,min=1"` // Expected responses
+	Deprecated  bool                  `json:"deprecated,omitempty"`                // Whether the operation is deprecated
+	Security    []map[string][]string `json:"security,omitempty"`                  // Security requirements
+	Servers     []Server              `json:"servers,omitempty"`                   // Servers overriding the document servers
+	Extensions  map[string]any        `json:"-"`                                   // Vendor extensions (x-*)
 }
 
 // Parameter represents a single parameter for an operation.
@@ -105,21 +130,59 @@ type RequestBody struct {
 type Response struct {
 	Description string               `json:"description" validate:"required"` // Response description
 	Content     map[string]MediaType `json:"content,omitempty"`               // Media types produced by the response
+	Headers     map[string]Header    `json:"headers,omitempty"`               // Headers sent with the response
+	Links       map[string]Link      `json:"links,omitempty"`                 // Operations reachable from the response
+}
+
+// Link describes an operation that can be reached using values of a response,
+// e.g. the status endpoint of an asynchronous operation.
+type Link struct {
+	OperationID string         `json:"operationId,omitempty"` // operationId of the linked operation
+	Parameters  map[string]any `json:"parameters,omitempty"`  // Parameters of the linked operation, values or runtime expressions
+	Description string         `json:"description,omitempty"` // Link description
+}
+
+// Header describes a single header sent with a response.
+type Header struct {
+	Description string  `json:"description,omitempty"` // Header description
+	Schema      *Schema `json:"schema,omitempty"`      // Schema defining the type
 }
 
 // MediaType represents the media type of a request or response body.
 type MediaType struct {
-	Schema *Schema `json:"schema,omitempty"` // Schema describing the type
+	Schema   *Schema             `json:"schema,omitempty"`   // Schema describing the type
+	Example  any                 `json:"example,omitempty"`  // Example of the payload
+	Encoding map[string]Encoding `json:"encoding,omitempty"` // Encoding of the properties, for multipart and form bodies
+}
+
+// Encoding describes how a single property of a multipart or form body is
+// encoded.
+type Encoding struct {
+	ContentType string            `json:"contentType,omitempty"` // Content type of the part, e.g. "image/png"
+	Headers     map[string]Header `json:"headers,omitempty"`     // Additional headers of the part
+	Style       string            `json:"style,omitempty"`       // Serialization style of the property
+	Explode     *bool             `json:"explode,omitempty"`     // Serialize arrays and objects as separate parameters, the default of the style when nil
 }
 
 // Schema represents the structure of a request or response body.
 type Schema struct {
-	Ref        string            `json:"$ref,omitempty"`       // Reference to a schema
-	Type       string            `json:"type,omitempty"`       // Data type (e.g., "string", "object")
-	Format     string            `json:"format,omitempty"`     // Data format (e.g., "uuid", "email")
-	Properties map[string]Schema `json:"properties,omitempty"` // Properties of the object
-	Items      *Schema           `json:"items,omitempty"`      // Schema for array items
-	Required   []string          `json:"required,omitempty"`   // Required properties
+	Ref                  string            `json:"$ref,omitempty"`                 // Reference to a schema
+	Type                 string            `json:"type,omitempty"`                 // Data type (e.g., "string", "object")
+	Format               string            `json:"format,omitempty"`               // Data format (e.g., "uuid", "email")
+	Description          string            `json:"description,omitempty"`          // Description of the value, may contain markdown
+	Properties           map[string]Schema `json:"properties,omitempty"`           // Properties of the object
+	Items                *Schema           `json:"items,omitempty"`                // Schema for array items
+	AdditionalProperties *Schema           `json:"additionalProperties,omitempty"` // Schema of the values of a map
+	Required             []string          `json:"required,omitempty"`             // Required properties
+	Default              any               `json:"default,omitempty"`              // Default value used when none is provided
+	Enum                 []any             `json:"enum,omitempty"`                 // Allowed values
+	Minimum              *float64          `json:"minimum,omitempty"`              // Inclusive lower bound of a number
+	AllOf                []Schema          `json:"allOf,omitempty"`                // Value must match all of the schemas
+	Nullable             bool              `json:"nullable,omitempty"`             // Whether null is allowed (OpenAPI 3.0)
+	OneOf                []Schema          `json:"oneOf,omitempty"`                // Value must match exactly one of the schemas
+	Extensions           map[string]any    `json:"-"`                              // Vendor extensions (x-*)
+
+	nullType bool // emit the type as [type, "null"], nullable in OpenAPI 3.1
 }
 
 // Components holds reusable components such as schemas and security schemes.
@@ -133,6 +196,8 @@ type SecurityScheme struct {
 	Type         string `json:"type" validate:"required"` // Security scheme type (e.g., "http", "apiKey")
 	Scheme       string `json:"scheme,omitempty"`         // HTTP Authorization scheme (e.g., "bearer")
 	BearerFormat string `json:"bearerFormat,omitempty"`   // Bearer token format
+	Name         string `json:"name,omitempty"`           // Name of the header, query or cookie parameter for "apiKey"
+	In           string `json:"in,omitempty"`             // Location of the API key: "header", "query" or "cookie"
 }
 
 // Tag represents a tag for an API operation.
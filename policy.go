@@ -0,0 +1,66 @@
+package router
+
+import (
+	"context"
+	"net/http"
+)
+
+// Policy decides whether a request may reach a route, based on the route's
+// documentation, e.g. requiring authentication for every route tagged admin.
+// It returns 0 to let the request through, or the status code the request is
+// rejected with.
+type Policy func(req *http.Request, docs Docs) int
+
+type routeDocsKey struct{}
+
+// UsePolicy registers a policy evaluated for every route registered with a
+// method, before the middlewares of the route. Policies run in registration
+// order, the first rejection ends the request. The Docs seen by a policy
+// include later changes made through the Route builder.
+func (r *Router) UsePolicy(policy Policy) {
+	rootRouter := r.rootParent()
+	rootRouter.mu.Lock()
+	defer rootRouter.mu.Unlock()
+
+	rootRouter.policies = append(rootRouter.policies, policy)
+}
+
+// RouteDocsFrom returns the Docs of the route serving the request, as made
+// available to middlewares and handlers of routes registered with a method.
+func RouteDocsFrom(ctx context.Context) (Docs, bool) {
+	docs, ok := ctx.Value(routeDocsKey{}).(Docs)
+	return docs, ok
+}
+
+// registerOperation registers the handler of a documented route, docsMethod
+// naming the route whose Docs apply, e.g. GET for automatic HEAD routes.
+func (r *Router) registerOperation(method, docsMethod, pattern string, handler http.Handler) {
+	rootRouter := r.rootParent()
+	key := docsMethod + " " + r.host + pattern
+	next := r.wrap(handler)
+
+	operation := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		rootRouter.mu.RLock()
+		docs := rootRouter.routeOps[key]
+		policies := rootRouter.policies
+		rootRouter.mu.RUnlock()
+
+		req = req.WithContext(context.WithValue(req.Context(), routeDocsKey{}, docs))
+		if docs.Deprecated {
+			w.Header().Set("Deprecation", "true")
+			if !docs.Sunset.IsZero() {
+				w.Header().Set("Sunset", docs.Sunset.UTC().Format(http.TimeFormat))
+			}
+		}
+		for _, policy := range policies {
+			if status := policy(req, docs); status != 0 {
+				http.Error(w, http.StatusText(status), status)
+				return
+			}
+		}
+
+		next.ServeHTTP(w, req)
+	})
+
+	r.mountRoute(method, pattern, operation, middlewareNames(r.middlewares))
+}
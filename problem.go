@@ -0,0 +1,47 @@
+package router
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// Problem is an RFC 7807 problem details object describing an error response.
+type Problem struct {
+	Type     string `json:"type,omitempty"`     // URI identifying the problem type, defaults to about:blank
+	Title    string `json:"title,omitempty"`    // Short summary of the problem type
+	Status   int    `json:"status,omitempty"`   // HTTP status code
+	Detail   string `json:"detail,omitempty"`   // Explanation specific to this occurrence
+	Instance string `json:"instance,omitempty"` // URI identifying this occurrence
+}
+
+// WriteProblem writes the problem as application/problem+json with its status
+// code, which defaults to 500. An empty Type defaults to about:blank and an
+// empty Title to the status text.
+func WriteProblem(w http.ResponseWriter, p Problem) error {
+	if p.Status == 0 {
+		p.Status = http.StatusInternalServerError
+	}
+	if p.Type == "" {
+		p.Type = "about:blank"
+	}
+	if p.Title == "" {
+		p.Title = http.StatusText(p.Status)
+	}
+
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.Header().Del("Content-Length")
+	w.WriteHeader(p.Status)
+
+	return json.NewEncoder(w).Encode(p)
+}
+
+// ProblemHandler returns a handler responding with the problem details of the
+// given status, for use with HandleStatus.
+func ProblemHandler(status int) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		_ = WriteProblem(w, Problem{
+			Status:   status,
+			Instance: req.URL.Path,
+		})
+	}
+}
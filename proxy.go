@@ -0,0 +1,60 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ProxyOptions configures Proxy.
+type ProxyOptions struct {
+	Headers http.Header   // Headers set on every request sent to the backend
+	Timeout time.Duration // Maximum time to wait for the backend's response headers, unlimited when zero
+}
+
+// Proxy forwards every request under the prefix to the backend at target,
+// through the router's middlewares. The prefix is stripped from the path before
+// it is joined to the target's path, and the Host header is set to the
+// target's host. Responses are never replaced by the HandleStatus handlers, and
+// flushing and connection upgrades reach the client, so streaming and WebSocket
+// backends work as expected.
+func (r *Router) Proxy(prefix string, target string, options ...ProxyOptions) error {
+	targetURL, err := url.Parse(target)
+	if err != nil {
+		return err
+	}
+
+	var opts ProxyOptions
+	if len(options) > 0 {
+		opts = options[0]
+	}
+
+	proxy := &httputil.ReverseProxy{
+		Rewrite: func(pr *httputil.ProxyRequest) {
+			pr.SetURL(targetURL)
+			pr.SetXForwarded()
+			for name, values := range opts.Headers {
+				pr.Out.Header[name] = values
+			}
+		},
+	}
+
+	if opts.Timeout > 0 {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.ResponseHeaderTimeout = opts.Timeout
+		proxy.Transport = transport
+	}
+
+	prefix = strings.TrimSuffix(r.basePath+prefix, "/")
+	handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		// the backend's statuses are final
+		w.Header().Set(HeaderFlagDoNotIntercept, "true")
+		proxy.ServeHTTP(w, req)
+	})
+
+	r.mountRoute("", prefix+"/", r.wrap(http.StripPrefix(prefix, handler)), middlewareNames(r.middlewares))
+
+	return nil
+}
@@ -0,0 +1,25 @@
+package router
+
+import "net/http"
+
+// withRecover recovers panics of the handler with the route's recover handler,
+// before any recover middleware of the router sees them. http.ErrAbortHandler
+// keeps propagating, as it is meant to abort the response.
+func withRecover(handler http.Handler, recoverHandler func(http.ResponseWriter, *http.Request, any)) http.Handler {
+	if recoverHandler == nil {
+		return handler
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		defer func() {
+			if rec := recover(); rec != nil {
+				if rec == http.ErrAbortHandler {
+					panic(rec)
+				}
+				recoverHandler(w, req, rec)
+			}
+		}()
+
+		handler.ServeHTTP(w, req)
+	})
+}
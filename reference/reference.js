@@ -0,0 +1,217 @@
+(function (global) {
+	"use strict";
+
+	var methods = ["get", "put", "post", "delete", "options", "head", "patch", "trace"];
+
+	function el(tag, attrs, children) {
+		var node = document.createElement(tag);
+		Object.keys(attrs || {}).forEach(function (key) {
+			node.setAttribute(key, attrs[key]);
+		});
+		(children || []).forEach(function (child) {
+			if (child === null || child === undefined) {
+				return;
+			}
+			node.appendChild(typeof child === "string" ? document.createTextNode(child) : child);
+		});
+		return node;
+	}
+
+	// deref follows a local $ref pointer.
+	function deref(spec, value) {
+		var seen = 0;
+		while (value && typeof value.$ref === "string" && value.$ref.indexOf("#/") === 0 && seen++ < 32) {
+			value = value.$ref.slice(2).split("/").reduce(function (node, key) {
+				return node && node[key.replace(/~1/g, "/").replace(/~0/g, "~")];
+			}, spec);
+		}
+		return value || {};
+	}
+
+	function typeName(spec, schema) {
+		if (schema && schema.allOf && schema.allOf.length === 1) {
+			return typeName(spec, schema.allOf[0]) + (schema.nullable ? " | null" : "");
+		}
+		if (schema && schema.$ref) {
+			return schema.$ref.split("/").pop();
+		}
+		schema = deref(spec, schema);
+		if (schema.type === "array") {
+			return "Array of " + typeName(spec, schema.items);
+		}
+		return (schema.type || "any") + (schema.format ? " <" + schema.format + ">" : "");
+	}
+
+	// sample builds an example value from a schema.
+	function sample(spec, schema, depth) {
+		if (depth > 8) {
+			return null;
+		}
+		if (schema && schema.example !== undefined) {
+			return schema.example;
+		}
+		schema = deref(spec, schema);
+		if (schema.enum) {
+			return schema.enum[0];
+		}
+		if (schema.oneOf || schema.anyOf || schema.allOf) {
+			return sample(spec, (schema.oneOf || schema.anyOf || schema.allOf)[0], depth + 1);
+		}
+		switch (schema.type) {
+		case "object":
+			var out = {};
+			Object.keys(schema.properties || {}).forEach(function (key) {
+				out[key] = sample(spec, schema.properties[key], depth + 1);
+			});
+			return out;
+		case "array":
+			return [sample(spec, schema.items, depth + 1)];
+		case "integer":
+		case "number":
+			return 0;
+		case "boolean":
+			return true;
+		case "string":
+			return schema.format === "date-time" ? "2019-08-24T14:15:22Z" : "string";
+		default:
+			return null;
+		}
+	}
+
+	function fields(spec, rows) {
+		return el("table", {}, rows.map(function (row) {
+			return el("tr", {}, [
+				el("td", {}, [row.name, row.required ? el("div", { class: "required" }, ["required"]) : null]),
+				el("td", {}, [
+					el("div", { class: "type" }, [row.type]),
+					row.description ? el("div", { class: "description" }, [row.description]) : null
+				])
+			]);
+		}));
+	}
+
+	function schemaFields(spec, schema) {
+		schema = deref(spec, schema);
+		var required = schema.required || [];
+		return fields(spec, Object.keys(schema.properties || {}).map(function (key) {
+			var prop = schema.properties[key];
+			return { name: key, required: required.indexOf(key) >= 0, type: typeName(spec, prop), description: deref(spec, prop).description };
+		}));
+	}
+
+	function operation(spec, method, path, op, id) {
+		var middle = el("div", { class: "middle" }, [
+			el("h2", {}, [op.summary || op.operationId || method.toUpperCase() + " " + path]),
+			op.description ? el("div", { class: "description" }, [op.description]) : null
+		]);
+
+		var params = op.parameters || [];
+		["path", "query", "header", "cookie"].forEach(function (location) {
+			var rows = params.map(function (param) { return deref(spec, param); }).filter(function (param) {
+				return param.in === location;
+			});
+			if (rows.length > 0) {
+				middle.appendChild(el("h5", {}, [location + " parameters"]));
+				middle.appendChild(fields(spec, rows.map(function (param) {
+					return { name: param.name, required: param.required, type: typeName(spec, param.schema), description: param.description };
+				})));
+			}
+		});
+
+		var body = op.requestBody && deref(spec, op.requestBody);
+		if (body && body.content) {
+			var bodyType = Object.keys(body.content)[0];
+			middle.appendChild(el("h5", {}, ["Request body schema: " + bodyType]));
+			middle.appendChild(schemaFields(spec, body.content[bodyType].schema));
+		}
+
+		var responses = op.responses || {};
+		middle.appendChild(el("h5", {}, ["Responses"]));
+		middle.appendChild(fields(spec, Object.keys(responses).map(function (code) {
+			return { name: code, type: "", description: deref(spec, responses[code]).description };
+		})));
+
+		var right = el("div", { class: "right" }, [
+			el("div", { class: "endpoint" }, [el("span", { class: "badge " + method }, [method]), " " + path])
+		]);
+		if (body && body.content) {
+			right.appendChild(el("h5", {}, ["Request sample"]));
+			right.appendChild(el("pre", {}, [JSON.stringify(sample(spec, body.content[Object.keys(body.content)[0]].schema, 0), null, 2)]));
+		}
+		Object.keys(responses).forEach(function (code) {
+			var content = deref(spec, responses[code]).content || {};
+			Object.keys(content).forEach(function (type) {
+				var media = content[type];
+				right.appendChild(el("h5", {}, ["Response sample " + code + " " + type]));
+				right.appendChild(el("pre", {}, [JSON.stringify(media.example !== undefined ? media.example : sample(spec, media.schema, 0), null, 2)]));
+			});
+		});
+
+		return el("div", { class: "section", id: id }, [middle, right]);
+	}
+
+	function render(spec, container) {
+		var info = spec.info || {};
+		var menu = el("nav", { class: "menu" }, []);
+		var content = el("div", { class: "api-content" }, [
+			el("div", { class: "section" }, [
+				el("div", { class: "middle" }, [
+					el("h1", {}, [(info.title || "API") + " (" + (info.version || "") + ")"]),
+					info.description ? el("div", { class: "description" }, [info.description]) : null
+				]),
+				el("div", { class: "right" }, [])
+			])
+		]);
+
+		var groups = {};
+		var order = [];
+		Object.keys(spec.paths || {}).forEach(function (path) {
+			methods.forEach(function (method) {
+				var op = spec.paths[path][method];
+				if (!op) {
+					return;
+				}
+				var tag = (op.tags && op.tags[0]) || "default";
+				if (!groups[tag]) {
+					groups[tag] = [];
+					order.push(tag);
+				}
+				groups[tag].push({ method: method, path: path, op: op });
+			});
+		});
+
+		var n = 0;
+		order.forEach(function (tag) {
+			menu.appendChild(el("h3", {}, [tag]));
+			groups[tag].forEach(function (entry) {
+				var id = "operation-" + n++;
+				menu.appendChild(el("a", { href: "#" + id }, [
+					el("span", { class: "badge " + entry.method }, [entry.method]),
+					entry.op.summary || entry.path
+				]));
+				content.appendChild(operation(spec, entry.method, entry.path, entry.op, id));
+			});
+		});
+
+		container.textContent = "";
+		container.appendChild(el("div", { class: "reference-wrap" }, [menu, content]));
+	}
+
+	global.APIReference = {
+		// init fetches the OpenAPI document from specURL and renders it into the
+		// container element.
+		init: function (specURL, container) {
+			fetch(specURL).then(function (res) {
+				if (!res.ok) {
+					throw new Error(res.status + " " + res.statusText);
+				}
+				return res.json();
+			}).then(function (spec) {
+				render(spec, container);
+			}).catch(function (err) {
+				container.textContent = "";
+				container.appendChild(el("div", { class: "error" }, ["Failed to load " + specURL + ": " + err.message]));
+			});
+		}
+	};
+})(window);
@@ -0,0 +1,155 @@
+package router
+
+import (
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// Route is a registered route whose documentation can be completed fluently:
+//
+//	r.Get("/users/{id}", handler).
+//		Summary("Get user").
+//		Tag("users").
+//		Response(http.StatusOK, User{})
+//
+// The route is served as soon as it is registered; every call updates its
+// operation in the OpenAPI document. Runtime behavior driven by Docs, such as
+// query defaults and body limits, is fixed at registration.
+type Route struct {
+	router  *Router
+	method  string
+	pattern string
+	handler http.HandlerFunc
+	docs    Docs
+
+	disabled bool // skipped by a conditional registration, never documented
+}
+
+func newRoute(r *Router, method, pattern string, handler http.HandlerFunc, docs ...Docs) *Route {
+	rt := &Route{
+		router:  r,
+		method:  method,
+		pattern: pattern,
+		handler: handler,
+	}
+
+	if len(docs) > 0 {
+		// copy the collections so the builder never alters the caller's Docs
+		rt.docs = docs[0]
+		rt.docs.Tags = slices.Clone(rt.docs.Tags)
+		rt.docs.Parameters = slices.Clone(rt.docs.Parameters)
+		rt.docs.Out = maps.Clone(rt.docs.Out)
+		rt.docs.In = maps.Clone(rt.docs.In)
+	}
+
+	return rt
+}
+
+// Summary sets the summary of the operation.
+func (rt *Route) Summary(summary string) *Route {
+	rt.docs.Summary = summary
+	return rt.update()
+}
+
+// Description sets the description of the operation.
+func (rt *Route) Description(description string) *Route {
+	rt.docs.Description = description
+	return rt.update()
+}
+
+// OperationID sets the operationId of the operation.
+func (rt *Route) OperationID(id string) *Route {
+	rt.docs.OperationID = id
+	return rt.update()
+}
+
+// Tag adds tags to the operation.
+func (rt *Route) Tag(tags ...string) *Route {
+	rt.docs.Tags = append(rt.docs.Tags, tags...)
+	return rt.update()
+}
+
+// Param adds a parameter to the operation.
+func (rt *Route) Param(param Parameter) *Route {
+	rt.docs.Parameters = append(rt.docs.Parameters, param)
+	return rt.update()
+}
+
+// Body documents the JSON request body of the operation.
+func (rt *Route) Body(object any) *Route {
+	if rt.docs.In == nil {
+		rt.docs.In = make(map[string]DocIn)
+	}
+	rt.docs.In["application/json"] = DocIn{Object: object}
+	return rt.update()
+}
+
+// Response documents a JSON response for the status code, the object may be
+// nil for responses without body. The description defaults to the status text.
+func (rt *Route) Response(status int, object any, description ...string) *Route {
+	if rt.docs.Out == nil {
+		rt.docs.Out = make(map[string]DocOut)
+	}
+
+	desc := http.StatusText(status)
+	if len(description) > 0 {
+		desc = description[0]
+	}
+
+	rt.docs.Out[strconv.Itoa(status)] = DocOut{
+		ApplicationType: "application/json",
+		Description:     desc,
+		Object:          object,
+	}
+	return rt.update()
+}
+
+// Accepted documents the operation as asynchronous: a 202 response whose
+// Location header points to the status endpoint, linked to the operation with
+// the given operationId. The parameters of the status operation may hold
+// runtime expressions, e.g. {"id": "$response.body#/id"}.
+func (rt *Route) Accepted(statusOperationID string, parameters map[string]any) *Route {
+	if rt.docs.Out == nil {
+		rt.docs.Out = make(map[string]DocOut)
+	}
+
+	rt.docs.Out[strconv.Itoa(http.StatusAccepted)] = DocOut{
+		Description: http.StatusText(http.StatusAccepted),
+		Headers: map[string]Header{
+			"Location": {Description: "URL of the operation status", Schema: &Schema{Type: "string", Format: "uri"}},
+		},
+		Links: map[string]Link{
+			"status": {OperationID: statusOperationID, Parameters: parameters},
+		},
+	}
+	return rt.update()
+}
+
+// Deprecate marks the operation deprecated in the OpenAPI document and makes
+// the route send a Deprecation header, along with a Sunset header announcing
+// its removal unless sunset is zero.
+func (rt *Route) Deprecate(sunset time.Time) *Route {
+	rt.docs.Deprecated = true
+	rt.docs.Sunset = sunset
+	return rt.update()
+}
+
+// Docs returns the documentation collected for the route.
+func (rt *Route) Docs() Docs {
+	return rt.docs
+}
+
+func (rt *Route) update() *Route {
+	if rt.disabled {
+		return rt
+	}
+
+	rt.router.recordRouteDoc(rt.method, rt.pattern, rt.docs)
+	if rt.router.openapiDocs {
+		rt.router.registerDocs(rt.method, rt.pattern, rt.handler, rt.docs)
+	}
+	return rt
+}
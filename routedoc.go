@@ -0,0 +1,95 @@
+package router
+
+import "slices"
+
+// RouteDoc is the lightweight documentation of a single route returned by
+// GetDocs, available without UseOpenapiDocs.
+type RouteDoc struct {
+	Method      string      // HTTP method, empty for routes matching any method
+	Pattern     string      // Full pattern, including the group base paths
+	Title       string      // Summary of the route
+	Description string      // Description of the route
+	Tags        []string    // Tags of the route
+	Params      []Parameter // Documented parameters
+}
+
+// RouteInfo describes a registered route, as returned by Routes.
+type RouteInfo struct {
+	Method  string   // HTTP method, empty for routes matching any method
+	Host    string   // Host the route is restricted to, empty for any host
+	Pattern string   // Full pattern, including the group base paths
+	Group   bool     // Whether the route was registered on a group
+	Tags    []string // Tags of the route
+	Summary string   // Summary of the route
+}
+
+// GetDocs returns a flat, human readable index of the registered routes in
+// registration order. Routes registered without Docs, such as static files, are
+// listed with their method and pattern only.
+func (r *Router) GetDocs() []RouteDoc {
+	rootRouter := r.rootParent()
+	rootRouter.mu.RLock()
+	defer rootRouter.mu.RUnlock()
+
+	docs := make([]RouteDoc, 0, len(rootRouter.routes))
+	for _, rt := range rootRouter.routes {
+		if doc, ok := rootRouter.routeDocs[rt.method+" "+rt.host+rt.pattern]; ok {
+			docs = append(docs, doc)
+			continue
+		}
+		docs = append(docs, RouteDoc{Method: rt.method, Pattern: rt.pattern})
+	}
+
+	return docs
+}
+
+// Routes returns every route registered on the router and its groups, in
+// registration order. It does not depend on UseOpenapiDocs.
+func (r *Router) Routes() []RouteInfo {
+	rootRouter := r.rootParent()
+	rootRouter.mu.RLock()
+	defer rootRouter.mu.RUnlock()
+
+	routes := make([]RouteInfo, 0, len(rootRouter.routes))
+	for _, rt := range rootRouter.routes {
+		doc := rootRouter.routeDocs[rt.method+" "+rt.host+rt.pattern]
+		routes = append(routes, RouteInfo{
+			Method:  rt.method,
+			Host:    rt.host,
+			Pattern: rt.pattern,
+			Group:   rt.group,
+			Tags:    slices.Clone(doc.Tags),
+			Summary: doc.Title,
+		})
+	}
+
+	return routes
+}
+
+// recordRouteDoc stores the lightweight documentation of a route, replacing any
+// previous one.
+func (r *Router) recordRouteDoc(method, pattern string, docs ...Docs) {
+	doc := RouteDoc{Method: method, Pattern: pattern}
+	if len(docs) > 0 {
+		doc.Title = docs[0].Summary
+		doc.Description = docs[0].Description
+		doc.Tags = slices.Clone(docs[0].Tags)
+		doc.Params = slices.Clone(docs[0].Parameters)
+	}
+
+	rootRouter := r.rootParent()
+	rootRouter.mu.Lock()
+	defer rootRouter.mu.Unlock()
+
+	if rootRouter.routeDocs == nil {
+		rootRouter.routeDocs = make(map[string]RouteDoc)
+		rootRouter.routeOps = make(map[string]Docs)
+	}
+	rootRouter.routeDocs[method+" "+r.host+pattern] = doc
+
+	var full Docs
+	if len(docs) > 0 {
+		full = docs[0]
+	}
+	rootRouter.routeOps[method+" "+r.host+pattern] = full
+}
@@ -1,12 +1,22 @@
 package router
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
+	"maps"
+	"net"
 	"net/http"
+	"os"
 	"reflect"
+	"runtime"
 	"slices"
 	"strings"
 	"sync"
+	"time"
+	"unicode"
 )
 
 var (
@@ -14,19 +24,57 @@ var (
 	DefaultRedirectStatusCode    = http.StatusTemporaryRedirect // or http.StatusMovedPermanently
 	DefaultUseOpenapiDocs        = false
 	OpenApiVersion               = "3.0.1"
+	DefaultResponseCode          = "default"
+	DefaultResponse              = &Response{Description: "Default response"}
+	DefaultOptionsStatusCode     = http.StatusNoContent
 )
 
 type (
 	Router struct {
-		mux                   *http.ServeMux
+		mux                   Mux
+		host                  string // host the routes are restricted to, set by Host
 		basePath              string
 		redirectTrailingSlash bool
 		openapiDocs           bool
+		defaultResponseCode   string
+		defaultResponse       *Response
+		handlerNamesForDocs   bool
+		autoTagByPath         bool
+		optionsAsterisk       bool
+		inlineSchemas         bool
+		optionsStatus         int
+		optionsBody           bool
+		responseHeaders       map[string]Header
+		namingStrategy        NamingStrategy
+		autoHead              bool
+		corsPreflight         bool // routes get an OPTIONS handler wrapped in their middlewares, see UseCORS
+		produces              []string
+		consumes              []string
 		middlewares           []Middleware
+		assetMiddlewares      []Middleware
 		parent                *Router // Reference to the parent router
 
 		handleStatus map[int]http.HandlerFunc
 		patternMap   map[string]string
+		optionsPaths map[string]*optionsPath // paths documented per host, keyed by host and stripped pattern, for their OPTIONS handler
+		notAllowed   map[string]bool         // host and pattern of the method not allowed handlers, see HandleMethodNotAllowed
+		preflights   map[string]bool         // host and pattern of the OPTIONS handlers registered by UseCORS routers
+		namedRoutes  map[string]string       // patterns of the routes named with Docs.Name
+		routes       []route
+		routeDocs    map[string]RouteDoc // keyed by method, host and pattern
+		routeOps     map[string]Docs     // full Docs of the routes, keyed by method, host and pattern
+		policies     []Policy
+		groupSlash   map[string]bool // trailing slash redirection set by groups, keyed by base path
+		openapiHooks []func(*OpenAPI)
+		contexts     []func(context.Context, *http.Request) context.Context
+		jsonConfig   *JSONConfig
+		encoders     *encoders
+
+		maxMultipartMemory int64
+
+		interfaceImpls map[reflect.Type][]reflect.Type
+		typeSchemas    map[reflect.Type]Schema
+		componentTypes map[string]reflect.Type // Go types of the generated component schemas, keyed by name
 
 		once    sync.Once
 		mu      sync.RWMutex
@@ -34,37 +82,92 @@ type (
 	}
 
 	Docs struct {
+		Name        string                // Name of the route, for URLFor
+		Title       string                // Deprecated: use Summary
+		Params      []DocsParam           // Deprecated: use Parameters
 		Tags        []string              // Tags for the operation
 		Summary     string                // Short summary of the operation
 		Description string                // Operation description
+		OperationID string                // Unique operation ID, derived from the pattern when empty
 		Parameters  []Parameter           // Parameters for the operation
 		RequestBody *RequestBody          // Request body for the operation
 		Responses   map[string]Response   // Expected responses
 		Security    []map[string][]string // Security requirements
+		Servers     []Server              // Servers overriding the document servers, those of the Host when empty
+		Extensions  map[string]any        // Vendor extensions (x-*) for the operation
+
+		Deprecated bool      // Marks the operation deprecated and sends a Deprecation header
+		Sunset     time.Time // Sent as Sunset header of deprecated operations, omitted when zero
+
+		ExampleProvider func(status string) any // Example payload per response code, nil to omit
+
+		MaxBodyBytes   int64                                         // Maximum request body size, unlimited when zero
+		RecoverHandler func(http.ResponseWriter, *http.Request, any) // Handles panics of this route, before any recover middleware
 
 		In  map[string]DocIn
 		Out map[string]DocOut
 	}
 
+	// DocsParam is the legacy, simplified form of a Parameter.
+	DocsParam struct {
+		Name        string // Parameter name, documented in the path when the pattern has a matching wildcard
+		Type        string // Schema type, string when empty
+		Description string // Parameter description
+	}
+
 	DocOut struct {
 		ApplicationType string
 		Description     string
 		Object          any
+		Example         any               // Example payload, replaced by Docs.ExampleProvider when it returns one
+		Headers         map[string]Header // Headers sent with the response
+		Links           map[string]Link   // Operations reachable from the response
 	}
 
 	DocIn struct {
 		Object   any
+		Example  any // Example payload
 		Required bool
+		Encoding map[string]Encoding // Encoding of the properties, for multipart and form bodies
+	}
+
+	// ServeFileOptions configures ServeFile.
+	ServeFileOptions struct {
+		Validate bool   // Check that the file exists at registration
+		Fallback string // File served when the file is missing at request time
 	}
 
 	Middleware func(http.Handler) http.Handler
+
+	// Mux is the request multiplexer the router registers its routes on,
+	// http.ServeMux by default. Patterns have the form "METHOD /path" with the
+	// wildcard syntax of http.ServeMux. Implementations that also provide
+	// Handler(*http.Request) (http.Handler, string) are used by AllowedMethods.
+	Mux interface {
+		Handle(pattern string, handler http.Handler)
+		ServeHTTP(w http.ResponseWriter, req *http.Request)
+	}
+
+	// route is a single entry of the route table, its handler is wrapped with
+	// the middlewares that applied at registration.
+	route struct {
+		method      string
+		host        string // empty for routes matching any host
+		pattern     string
+		handler     http.Handler
+		middlewares []string // names of the middlewares wrapping the handler, outermost first
+		group       bool     // registered on a group rather than the root router
+	}
 )
 
-func New(ht *http.ServeMux, title string, version string) *Router {
+func New(ht Mux, title string, version string) *Router {
 	return &Router{
 		mux:                   ht,
 		redirectTrailingSlash: DefaultRedirectTrailingSlash,
 		openapiDocs:           DefaultUseOpenapiDocs,
+		defaultResponseCode:   DefaultResponseCode,
+		defaultResponse:       DefaultResponse,
+		optionsStatus:         DefaultOptionsStatusCode,
 		openapi: &OpenAPI{
 			Openapi: OpenApiVersion,
 			Info: Info{
@@ -79,9 +182,37 @@ func New(ht *http.ServeMux, title string, version string) *Router {
 		},
 		handleStatus: make(map[int]http.HandlerFunc),
 		patternMap:   make(map[string]string),
+		groupSlash:   make(map[string]bool),
 	}
 }
 
+// NewDefault creates a router on a fresh http.ServeMux, documented as "API"
+// version "0.0.0".
+func NewDefault() *Router {
+	return New(http.NewServeMux(), "API", "0.0.0")
+}
+
+// SetInfoSummary sets the short summary of the API. The summary was introduced
+// in OpenAPI 3.1 and is omitted from documents of earlier versions.
+func (r *Router) SetInfoSummary(summary string) {
+	rootRouter := r.rootParent()
+	rootRouter.mu.Lock()
+	defer rootRouter.mu.Unlock()
+
+	rootRouter.openapi.Info.Summary = summary
+}
+
+// SetJSONSchemaDialect sets the default $schema of the schemas in the document,
+// e.g. "https://json-schema.org/draft/2020-12/schema". The dialect was
+// introduced in OpenAPI 3.1 and is omitted from documents of earlier versions.
+func (r *Router) SetJSONSchemaDialect(dialect string) {
+	rootRouter := r.rootParent()
+	rootRouter.mu.Lock()
+	defer rootRouter.mu.Unlock()
+
+	rootRouter.openapi.JSONSchemaDialect = dialect
+}
+
 func (r *Router) AddServerEndpoint(url string, description string) {
 	r.openapi.Servers = append(r.openapi.Servers, Server{
 		URL:         url,
@@ -89,59 +220,354 @@ func (r *Router) AddServerEndpoint(url string, description string) {
 	})
 }
 
-func (r *Router) Get(pattern string, handler http.HandlerFunc, doc ...Docs) {
-	r.handle(http.MethodGet, pattern, handler, doc...)
+func (r *Router) Get(pattern string, handler http.HandlerFunc, doc ...Docs) *Route {
+	return r.handle(http.MethodGet, pattern, handler, doc...)
+}
+
+func (r *Router) Head(pattern string, handler http.HandlerFunc, doc ...Docs) *Route {
+	return r.handle(http.MethodHead, pattern, handler, doc...)
+}
+
+func (r *Router) Post(pattern string, handler http.HandlerFunc, doc ...Docs) *Route {
+	return r.handle(http.MethodPost, pattern, handler, doc...)
+}
+
+func (r *Router) Put(pattern string, handler http.HandlerFunc, doc ...Docs) *Route {
+	return r.handle(http.MethodPut, pattern, handler, doc...)
+}
+
+func (r *Router) Patch(pattern string, handler http.HandlerFunc, doc ...Docs) *Route {
+	return r.handle(http.MethodPatch, pattern, handler, doc...)
+}
+
+func (r *Router) Delete(pattern string, handler http.HandlerFunc, doc ...Docs) *Route {
+	return r.handle(http.MethodDelete, pattern, handler, doc...)
+}
+
+// GetHandler registers a GET route served by an http.Handler, such as a
+// handler from the standard library or a third-party package.
+func (r *Router) GetHandler(pattern string, handler http.Handler, doc ...Docs) *Route {
+	return r.handle(http.MethodGet, pattern, handlerFunc(handler), doc...)
+}
+
+// HeadHandler registers a HEAD route served by an http.Handler, see GetHandler.
+func (r *Router) HeadHandler(pattern string, handler http.Handler, doc ...Docs) *Route {
+	return r.handle(http.MethodHead, pattern, handlerFunc(handler), doc...)
+}
+
+// PostHandler registers a POST route served by an http.Handler, see GetHandler.
+func (r *Router) PostHandler(pattern string, handler http.Handler, doc ...Docs) *Route {
+	return r.handle(http.MethodPost, pattern, handlerFunc(handler), doc...)
+}
+
+// PutHandler registers a PUT route served by an http.Handler, see GetHandler.
+func (r *Router) PutHandler(pattern string, handler http.Handler, doc ...Docs) *Route {
+	return r.handle(http.MethodPut, pattern, handlerFunc(handler), doc...)
 }
 
-func (r *Router) Head(pattern string, handler http.HandlerFunc, doc ...Docs) {
-	r.handle(http.MethodHead, pattern, handler, doc...)
+// PatchHandler registers a PATCH route served by an http.Handler, see GetHandler.
+func (r *Router) PatchHandler(pattern string, handler http.Handler, doc ...Docs) *Route {
+	return r.handle(http.MethodPatch, pattern, handlerFunc(handler), doc...)
 }
 
-func (r *Router) Post(pattern string, handler http.HandlerFunc, doc ...Docs) {
-	r.handle(http.MethodPost, pattern, handler, doc...)
+// DeleteHandler registers a DELETE route served by an http.Handler, see GetHandler.
+func (r *Router) DeleteHandler(pattern string, handler http.Handler, doc ...Docs) *Route {
+	return r.handle(http.MethodDelete, pattern, handlerFunc(handler), doc...)
 }
 
-func (r *Router) Put(pattern string, handler http.HandlerFunc, doc ...Docs) {
-	r.handle(http.MethodPut, pattern, handler, doc...)
+// handlerFunc adapts an http.Handler, keeping nil handlers nil so that handle
+// reports them.
+func handlerFunc(handler http.Handler) http.HandlerFunc {
+	if handler == nil {
+		return nil
+	}
+	if fn, ok := handler.(http.HandlerFunc); ok {
+		return fn
+	}
+	return handler.ServeHTTP
+}
+
+// GetIf registers the GET route only when cond is true, e.g. for routes behind
+// a feature flag. A disabled route is neither served nor documented; the
+// returned Route can still be used but has no effect.
+func (r *Router) GetIf(cond bool, pattern string, handler http.HandlerFunc, doc ...Docs) *Route {
+	return r.handleIf(cond, http.MethodGet, pattern, handler, doc...)
+}
+
+// HeadIf registers the HEAD route only when cond is true, see GetIf.
+func (r *Router) HeadIf(cond bool, pattern string, handler http.HandlerFunc, doc ...Docs) *Route {
+	return r.handleIf(cond, http.MethodHead, pattern, handler, doc...)
+}
+
+// PostIf registers the POST route only when cond is true, see GetIf.
+func (r *Router) PostIf(cond bool, pattern string, handler http.HandlerFunc, doc ...Docs) *Route {
+	return r.handleIf(cond, http.MethodPost, pattern, handler, doc...)
+}
+
+// PutIf registers the PUT route only when cond is true, see GetIf.
+func (r *Router) PutIf(cond bool, pattern string, handler http.HandlerFunc, doc ...Docs) *Route {
+	return r.handleIf(cond, http.MethodPut, pattern, handler, doc...)
+}
+
+// PatchIf registers the PATCH route only when cond is true, see GetIf.
+func (r *Router) PatchIf(cond bool, pattern string, handler http.HandlerFunc, doc ...Docs) *Route {
+	return r.handleIf(cond, http.MethodPatch, pattern, handler, doc...)
 }
 
-func (r *Router) Patch(pattern string, handler http.HandlerFunc, doc ...Docs) {
-	r.handle(http.MethodPatch, pattern, handler, doc...)
+// DeleteIf registers the DELETE route only when cond is true, see GetIf.
+func (r *Router) DeleteIf(cond bool, pattern string, handler http.HandlerFunc, doc ...Docs) *Route {
+	return r.handleIf(cond, http.MethodDelete, pattern, handler, doc...)
 }
 
-func (r *Router) Delete(pattern string, handler http.HandlerFunc, doc ...Docs) {
-	r.handle(http.MethodDelete, pattern, handler, doc...)
+func (r *Router) handleIf(cond bool, method, pattern string, handler http.HandlerFunc, docs ...Docs) *Route {
+	if cond {
+		return r.handle(method, pattern, handler, docs...)
+	}
+
+	rt := newRoute(r, method, r.basePath+pattern, handler, docs...)
+	rt.disabled = true
+	return rt
 }
 
 func (r *Router) Group(basePath string, fn func(*Router)) {
-	subRouter := &Router{
+	fn(r.subRouter(basePath))
+}
+
+// Host returns a router whose routes, including those of its groups, only
+// match requests for the given host, e.g.
+//
+//	api := r.Host("api.example.com")
+//	api.Use(middleware.Recover)
+//	api.Group("/v1", func(v1 *router.Router) {
+//		v1.Get("/users", listUsers)
+//	})
+//
+// Host routes take precedence over routes of the same path without host. The
+// operations of host routes are documented with the host as their server;
+// OpenAPI paths are not keyed by host, so a path registered on several hosts is
+// documented once, by the last registration.
+func (r *Router) Host(host string) *Router {
+	subRouter := r.subRouter("")
+	subRouter.host = host
+	return subRouter
+}
+
+// subRouter returns a router inheriting the configuration and middlewares of
+// r, for routes under the additional base path.
+func (r *Router) subRouter(basePath string) *Router {
+	return &Router{
+		host:                  r.host,
 		basePath:              r.basePath + basePath,
 		redirectTrailingSlash: r.redirectTrailingSlash,
 		middlewares:           append([]Middleware{}, r.middlewares...),
+		assetMiddlewares:      append([]Middleware{}, r.assetMiddlewares...),
 		parent:                r,
 		openapiDocs:           r.openapiDocs,
+		defaultResponseCode:   r.defaultResponseCode,
+		defaultResponse:       r.defaultResponse,
+		handlerNamesForDocs:   r.handlerNamesForDocs,
+		autoTagByPath:         r.autoTagByPath,
+		inlineSchemas:         r.inlineSchemas,
+		responseHeaders:       maps.Clone(r.responseHeaders),
+		namingStrategy:        r.namingStrategy,
+		autoHead:              r.autoHead,
+		corsPreflight:         r.corsPreflight,
+		produces:              r.produces,
+		consumes:              r.consumes,
 		handleStatus:          r.handleStatus,
 	}
-
-	fn(subRouter)
 }
 
+// RedirectTrailingSlash configures the redirection of paths with a trailing
+// slash to their counterpart without. When called on a group it only applies to
+// paths under the group's base path, overriding the parent's setting.
 func (r *Router) RedirectTrailingSlash(redirect bool) {
 	r.redirectTrailingSlash = redirect
+
+	if r.parent == nil {
+		return
+	}
+
+	rootRouter := r.rootParent()
+	rootRouter.mu.Lock()
+	defer rootRouter.mu.Unlock()
+
+	rootRouter.groupSlash[strings.TrimSuffix(r.basePath, "/")] = redirect
+}
+
+// shouldRedirectTrailingSlash reports whether the path is covered by trailing
+// slash redirection, the setting of the most specific group winning.
+func (r *Router) shouldRedirectTrailingSlash(path string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var (
+		redirect = r.redirectTrailingSlash
+		longest  = -1
+	)
+
+	for prefix, groupRedirect := range r.groupSlash {
+		if len(prefix) > longest && strings.HasPrefix(path, prefix+"/") {
+			redirect = groupRedirect
+			longest = len(prefix)
+		}
+	}
+
+	return redirect
 }
 
 func (r *Router) UseOpenapiDocs(use bool) {
 	r.openapiDocs = use
 }
 
+// UseHandlerNamesForDocs derives the summary and operationId of an operation
+// from the handler function name when they are not given explicitly. Routes
+// registered without Docs are documented as well when enabled.
+func (r *Router) UseHandlerNamesForDocs(use bool) {
+	r.handlerNamesForDocs = use
+}
+
+// AutoTagByPath tags operations without explicit tags with the first static
+// segment of their path, e.g. GET /users/{id} is tagged users.
+func (r *Router) AutoTagByPath(auto bool) {
+	r.autoTagByPath = auto
+}
+
+// HandleOptionsAsterisk answers the server-wide OPTIONS * request with an Allow
+// header listing every method the router serves. Note that http.Server answers
+// these requests itself unless DisableGeneralOptionsHandler is set.
+func (r *Router) HandleOptionsAsterisk(handle bool) {
+	r.optionsAsterisk = handle
+}
+
+// UseResponseHeaders documents headers on every response of the operations
+// registered afterwards, such as the X-RateLimit-* headers set by a rate limiting
+// middleware. Headers documented on a response itself take precedence.
+func (r *Router) UseResponseHeaders(headers map[string]Header) {
+	if r.responseHeaders == nil {
+		r.responseHeaders = make(map[string]Header, len(headers))
+	}
+	maps.Copy(r.responseHeaders, headers)
+}
+
+// Produces sets the media types documented for responses whose DocOut has an
+// object but no ApplicationType, e.g. Produces("application/json") for a JSON
+// only group.
+func (r *Router) Produces(mediaTypes ...string) {
+	r.produces = mediaTypes
+}
+
+// Consumes sets the media types documented for request bodies declared in
+// Docs.In under an empty content type.
+func (r *Router) Consumes(mediaTypes ...string) {
+	r.consumes = mediaTypes
+}
+
+// OptionsStatus sets the status code of the automatic OPTIONS responses, 204 No
+// Content by default.
+func (r *Router) OptionsStatus(code int) {
+	r.rootParent().optionsStatus = code
+}
+
+// OptionsBody adds a JSON body listing the allowed methods to the automatic
+// OPTIONS responses, e.g. {"allow":["OPTIONS","GET"]}. The body is omitted when
+// the status is 204 No Content, which does not permit one.
+func (r *Router) OptionsBody(include bool) {
+	r.rootParent().optionsBody = include
+}
+
+// InlineSchemas embeds the schemas of documented objects directly in the
+// operations instead of referencing component schemas. Components are only
+// created for self-referencing types, which cannot be inlined.
+func (r *Router) InlineSchemas(inline bool) {
+	r.inlineSchemas = inline
+}
+
+// UseDefaultResponse sets the response documented for operations that declare
+// no responses of their own. Passing a nil response disables the fallback.
+func (r *Router) UseDefaultResponse(code string, response *Response) {
+	r.defaultResponseCode = code
+	r.defaultResponse = response
+}
+
+// HandleStatus registers a handler that replaces any response with the given
+// status code. Handlers for statuses produced by the mux itself (404 and 405)
+// run inside the global middleware chain, so logging and metrics observe them.
 func (r *Router) HandleStatus(httpStatus int, handler http.HandlerFunc) {
 	r.handleStatus[httpStatus] = handler
 }
 
+// HandleMethodNotAllowed registers the handler answering requests to pattern
+// whose method has no route, taking precedence over HandleStatus for 405. The
+// Allow header is set before the handler runs, which writes the response,
+// typically with status 405. The router's middlewares wrap the handler.
+func (r *Router) HandleMethodNotAllowed(pattern string, handler http.HandlerFunc) {
+	pattern = r.basePath + pattern
+	if handler == nil {
+		panic(fmt.Sprintf("router: nil method not allowed handler for %s", pattern))
+	}
+
+	rootRouter := r.rootParent()
+	rootRouter.markNotAllowed(r.host + pattern)
+
+	notAllowed := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		allowed := rootRouter.AllowedMethods(req)
+		if allowed == nil {
+			allowed = rootRouter.getMethodsForPattern(req.Host, strings.ReplaceAll(pattern, "{$}", ""))
+		}
+		w.Header().Set("Allow", strings.Join(allowed, ", "))
+		w.Header().Set(HeaderFlagDoNotIntercept, "true")
+		handler(w, req)
+	})
+
+	// A pattern without method matches every method the routes of the same
+	// pattern do not handle.
+	r.mountRoute("", pattern, r.wrap(notAllowed), middlewareNames(r.middlewares))
+}
+
+// markNotAllowed records the host and pattern of a method not allowed handler,
+// which AllowedMethods does not count as a route of the path.
+func (r *Router) markNotAllowed(key string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.notAllowed == nil {
+		r.notAllowed = make(map[string]bool)
+	}
+	r.notAllowed[key] = true
+}
+
 func (r *Router) Use(middleware Middleware) {
 	r.middlewares = append(r.middlewares, middleware)
 }
 
+// UseContext registers a decorator that derives the context of every request
+// before it is dispatched, e.g. to attach a database handle or feature flags.
+// Decorators run in the order they were registered.
+func (r *Router) UseContext(fn func(context.Context, *http.Request) context.Context) {
+	rootRouter := r.rootParent()
+	rootRouter.contexts = append(rootRouter.contexts, fn)
+}
+
+// UseForAssets registers middlewares that only wrap the routes serving the
+// OpenAPI document, documentation pages and static files registered afterwards,
+// e.g. UseForAssets(middleware.Compress) to compress large specs and scripts
+// without compressing the API responses. They run inside the router's
+// middlewares.
+func (r *Router) UseForAssets(middlewares ...Middleware) {
+	r.assetMiddlewares = append(r.assetMiddlewares, middlewares...)
+}
+
+// wrapAsset applies the asset middlewares to the handler, the first registered
+// middleware being the outermost.
+func (r *Router) wrapAsset(handler http.Handler) http.Handler {
+	for i := len(r.assetMiddlewares) - 1; i >= 0; i-- {
+		handler = r.assetMiddlewares[i](handler)
+	}
+
+	return handler
+}
+
 func (r *Router) ServeFiles(pattern string, fs http.FileSystem) {
 	if r.basePath != "" {
 		pattern = r.basePath + pattern
@@ -155,59 +581,102 @@ func (r *Router) ServeFiles(pattern string, fs http.FileSystem) {
 	// Create a file server handler
 	fileServer := http.StripPrefix(pattern, http.FileServer(fs))
 
-	// Wrap the file server with middlewares
-	var finalHandler http.Handler = fileServer
-	for i := len(r.middlewares) - 1; i >= 0; i-- {
-		finalHandler = r.middlewares[i](finalHandler)
-	}
-
 	// Register the handler for GET method
-	r.mux.Handle("GET "+pattern, finalHandler)
+	r.registerRoute(http.MethodGet, pattern, r.wrapAsset(fileServer))
 }
 
-func (r *Router) ServeFile(pattern string, filepath string) {
+// ServeFile serves a single file. With ServeFileOptions.Validate the file is
+// checked at registration and an error is returned when it is missing; the
+// route is registered regardless, so a Fallback can still be served.
+func (r *Router) ServeFile(pattern string, filepath string, options ...ServeFileOptions) error {
 	if r.basePath != "" {
 		pattern = r.basePath + pattern
 	}
 
+	var opts ServeFileOptions
+	if len(options) > 0 {
+		opts = options[0]
+	}
+
+	var err error
+	if opts.Validate {
+		if _, statErr := os.Stat(filepath); statErr != nil {
+			err = fmt.Errorf("router: serve file %s: %w", pattern, statErr)
+		}
+	}
+
 	// Handler to serve the file
 	handler := func(w http.ResponseWriter, req *http.Request) {
-		http.ServeFile(w, req, filepath)
-	}
+		if opts.Fallback != "" {
+			if _, statErr := os.Stat(filepath); errors.Is(statErr, fs.ErrNotExist) {
+				http.ServeFile(w, req, opts.Fallback)
+				return
+			}
+		}
 
-	// Wrap the handler with middlewares
-	var finalHandler http.Handler = http.HandlerFunc(handler)
-	for i := len(r.middlewares) - 1; i >= 0; i-- {
-		finalHandler = r.middlewares[i](finalHandler)
+		http.ServeFile(w, req, filepath)
 	}
 
 	// Register the handler for GET method
-	fullPattern := "GET " + pattern
-	r.mux.Handle(fullPattern, finalHandler)
+	r.registerRoute(http.MethodGet, pattern, r.wrapAsset(http.HandlerFunc(handler)))
+
+	return err
 }
 
 func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	// just before serving add all the option handlers based on the openapi paths
 	if r.openapiDocs {
 		r.once.Do(func() {
-			for p, _ := range r.openapi.Paths {
-				fmt.Println("Registering options handler for", p)
+			r.mu.RLock()
+			paths := slices.Collect(maps.Values(r.optionsPaths))
+			r.mu.RUnlock()
+			for _, p := range paths {
 				r.registerOptionsHandler(p)
 			}
 		})
 	}
 
-	if r.redirectTrailingSlash {
-		if req.URL.Path != "/" && req.URL.Path[len(req.URL.Path)-1] == '/' {
+	if r.jsonConfig != nil {
+		req = withJSONConfig(req, r.jsonConfig)
+	}
+
+	if r.encoders != nil {
+		req = withEncoders(req, r.encoders)
+	}
+
+	if len(r.contexts) > 0 {
+		ctx := req.Context()
+		for _, fn := range r.contexts {
+			ctx = fn(ctx, req)
+		}
+		req = req.WithContext(ctx)
+	}
+
+	if r.optionsAsterisk && req.Method == http.MethodOptions && req.RequestURI == "*" {
+		r.writeOptions(w, r.serverMethods())
+		return
+	}
+
+	if len(req.URL.Path) > 1 && strings.HasSuffix(req.URL.Path, "/") {
+		if r.shouldRedirectTrailingSlash(req.URL.Path) {
 			http.Redirect(w, req, req.URL.Path[:len(req.URL.Path)-1], DefaultRedirectStatusCode)
 			return
 		}
 	}
 
+	stats := &ResponseStats{}
+	matched := new(bool)
+	ctx := context.WithValue(req.Context(), responseStatsKey{}, stats)
+	req = req.WithContext(context.WithValue(ctx, routeMatchedKey{}, matched))
+
+	req, multipart := withMultipartState(req, r.maxMultipartMemory)
+	defer multipart.cleanup()
+
 	interceptor := &routingStatusInterceptWriter{
 		ResponseWriter: &excludeHeaderWriter{
 			ResponseWriter:  w,
 			excludedHeaders: []string{HeaderFlagDoNotIntercept},
+			stats:           stats,
 		},
 		interceptMap: make(map[int]func() bool),
 	}
@@ -224,22 +693,43 @@ func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 		switch {
 		case interceptor.statusCode == http.StatusMethodNotAllowed:
 			// Set the Allow header
-			pattern := req.URL.Path
-			allowedMethods := r.getMethodsForPattern(pattern)
+			allowedMethods := r.getMethodsForPattern(req.Host, req.URL.Path)
 			if len(allowedMethods) > 0 {
 				interceptor.ResponseWriter.Header().Set("Allow", strings.Join(allowedMethods, ", "))
 			}
 
-			r.handleStatus[http.StatusMethodNotAllowed].ServeHTTP(interceptor.ResponseWriter, req)
+			r.serveStatus(r.handleStatus[http.StatusMethodNotAllowed], interceptor.ResponseWriter, req, *matched)
 		default:
 			if v, ok := r.handleStatus[interceptor.statusCode]; ok {
-				v.ServeHTTP(interceptor.ResponseWriter, req)
+				r.serveStatus(v, interceptor.ResponseWriter, req, *matched)
 			}
 		}
 	}
 }
 
-func (r *Router) handle(method, pattern string, handler http.HandlerFunc, docs ...Docs) {
+// serveStatus runs a custom status handler. When the status was produced by the
+// mux itself (no route matched the request) no middleware has seen the request
+// yet, so the handler is wrapped with the global middleware chain. Statuses
+// written by a matched route are already inside that route's chain.
+func (r *Router) serveStatus(handler http.Handler, w http.ResponseWriter, req *http.Request, matched bool) {
+	if !matched {
+		handler = r.rootParent().wrap(handler)
+	}
+
+	handler.ServeHTTP(w, req)
+}
+
+// wrap applies the router's middlewares to the handler, the first registered
+// middleware being the outermost.
+func (r *Router) wrap(handler http.Handler) http.Handler {
+	for i := len(r.middlewares) - 1; i >= 0; i-- {
+		handler = r.middlewares[i](handler)
+	}
+
+	return handler
+}
+
+func (r *Router) handle(method, pattern string, handler http.HandlerFunc, docs ...Docs) *Route {
 	if r.basePath != "" {
 		pattern = r.basePath + pattern
 	}
@@ -250,33 +740,91 @@ func (r *Router) handle(method, pattern string, handler http.HandlerFunc, docs .
 		pattern = "/" + pattern
 	}
 
-	r.registerRoute(method, pattern, handler)
+	if handler == nil {
+		panic(fmt.Sprintf("router: nil handler for %s %s", method, pattern))
+	}
+
+	var finalHandler http.Handler = handler
+	if len(docs) > 0 {
+		docs = append([]Docs{docs[0].withLegacyFields(pattern)}, docs[1:]...)
+		finalHandler = withQueryDefaults(handler, docs[0].Parameters)
+		finalHandler = withMaxBodyBytes(finalHandler, docs[0].MaxBodyBytes)
+		finalHandler = withRecover(finalHandler, docs[0].RecoverHandler)
+	}
+
+	r.registerOperation(method, method, pattern, finalHandler)
+	if method == http.MethodGet && r.autoHead {
+		r.registerOperation(http.MethodHead, method, pattern, headHandler(finalHandler))
+	}
+	if r.corsPreflight {
+		r.registerPreflight(pattern)
+	}
+	r.recordRouteDoc(method, pattern, docs...)
+	if len(docs) > 0 && docs[0].Name != "" {
+		r.nameRoute(docs[0].Name, pattern)
+	}
 	if r.openapiDocs {
-		r.registerDocs(method, pattern, docs...)
+		r.registerDocs(method, pattern, handler, docs...)
 	}
+
+	return newRoute(r, method, pattern, handler, docs...)
 }
 
-func (r *Router) registerRoute(method, pattern string, handler http.HandlerFunc) {
-	var (
-		fullPattern               = method + " " + pattern
-		finalHandler http.Handler = handler
-	)
+func (r *Router) registerRoute(method, pattern string, handler http.Handler) {
+	if fn, ok := handler.(http.HandlerFunc); handler == nil || ok && fn == nil {
+		panic(fmt.Sprintf("router: nil handler for %s %s", method, pattern))
+	}
 
-	for i := len(r.middlewares) - 1; i >= 0; i-- {
-		finalHandler = r.middlewares[i](finalHandler)
+	r.mountRoute(method, pattern, r.wrap(handler), middlewareNames(r.middlewares))
+}
+
+// mountRoute registers an already wrapped handler on the root mux and records
+// it in the route table. An empty method matches any method.
+func (r *Router) mountRoute(method, pattern string, handler http.Handler, middlewares []string) {
+	r.rootParent().mount(route{
+		method:      method,
+		host:        r.host,
+		pattern:     pattern,
+		handler:     handler,
+		middlewares: middlewares,
+		group:       r.parent != nil,
+	})
+}
+
+// mount registers the handler of a route on the mux of the root router and
+// records the route in its route table.
+func (r *Router) mount(rt route) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if rt.method == "" {
+		r.mux.Handle(rt.host+rt.pattern, markMatched(rt.handler))
+	} else {
+		r.mux.Handle(rt.method+" "+rt.host+rt.pattern, markMatched(rt.handler))
 	}
+	r.routes = append(r.routes, rt)
+}
 
-	rootRouter := r.rootParent()
-	rootRouter.mu.Lock()
-	defer rootRouter.mu.Unlock()
+type routeMatchedKey struct{}
 
-	rootRouter.mux.Handle(fullPattern, finalHandler)
-	return
+// markMatched flags the request as matched by a route of the router, so
+// ServeHTTP can tell statuses of routes from those of the mux whatever Mux is
+// used.
+func markMatched(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if matched, ok := req.Context().Value(routeMatchedKey{}).(*bool); ok {
+			*matched = true
+		}
+		handler.ServeHTTP(w, req)
+	})
 }
 
-func (r *Router) registerDocs(method, pattern string, docs ...Docs) {
+func (r *Router) registerDocs(method, pattern string, handler http.HandlerFunc, docs ...Docs) {
 	if len(docs) == 0 {
-		return
+		if !r.handlerNamesForDocs {
+			return
+		}
+		docs = []Docs{{}}
 	}
 
 	var (
@@ -289,6 +837,18 @@ func (r *Router) registerDocs(method, pattern string, docs ...Docs) {
 	defer rootRouter.mu.Unlock()
 
 	rootRouter.patternMap[stripPattern] = pattern
+	options, ok := rootRouter.optionsPaths[r.host+stripPattern]
+	if !ok {
+		if rootRouter.optionsPaths == nil {
+			rootRouter.optionsPaths = make(map[string]*optionsPath)
+		}
+		options = &optionsPath{host: r.host, pattern: pattern, middlewares: slices.Clone(r.middlewares)}
+		rootRouter.optionsPaths[r.host+stripPattern] = options
+	}
+	options.methods = addIfMissing(options.methods, method, false)
+	if method == http.MethodGet && r.autoHead {
+		options.methods = addIfMissing(options.methods, http.MethodHead, false)
+	}
 
 	// Get or create RouteInfo for the pattern
 	pathItem, exists := rootRouter.openapi.Paths[pattern]
@@ -300,41 +860,103 @@ func (r *Router) registerDocs(method, pattern string, docs ...Docs) {
 		Tags:        doc.Tags,
 		Summary:     doc.Summary,
 		Description: doc.Description,
-		OperationID: fmt.Sprintf("%s%s", method, r.OperationID(stripPattern)),
+		OperationID: doc.OperationID,
 		Parameters:  doc.Parameters,
 		RequestBody: doc.RequestBody,
 		Responses:   doc.Responses,
+		Deprecated:  doc.Deprecated,
 		Security:    doc.Security,
+		Servers:     doc.Servers,
+		Extensions:  doc.Extensions,
+	}
+	if r.host != "" && len(op.Servers) == 0 {
+		op.Servers = []Server{{URL: "https://" + r.host}}
 	}
 
-	// handle doc out
-	componentSchema, routeResponse := r.handleDocOut(doc.Out, rootRouter.openapi.Components.Schemas)
-	if componentSchema != nil {
-		for na, cs := range componentSchema {
-			if _, ex := rootRouter.openapi.Components.Schemas[na]; ex {
-				continue
+	if r.handlerNamesForDocs {
+		if name := handlerName(handler); name != "" {
+			if op.Summary == "" {
+				op.Summary = humanize(name)
+			}
+			if op.OperationID == "" {
+				op.OperationID = name
 			}
-			rootRouter.openapi.Components.Schemas[na] = cs
 		}
 	}
 
+	if op.OperationID == "" {
+		op.OperationID = r.namingStrategy.operationID(method, stripPattern)
+	}
+
+	if len(op.Tags) == 0 && r.autoTagByPath {
+		if tag := pathTag(stripPattern); tag != "" {
+			op.Tags = []string{tag}
+			if !slices.ContainsFunc(rootRouter.openapi.Tags, func(t Tag) bool { return t.Name == tag }) {
+				rootRouter.openapi.Tags = append(rootRouter.openapi.Tags, Tag{Name: tag})
+			}
+		}
+	}
+
+	// handle doc out
+	outSchemas, routeResponse := r.handleDocOut(doc.Out)
 	if routeResponse != nil {
 		op.Responses = routeResponse
 	}
 
 	// handle doc in
-	componentSchema, requestBody := r.handleDocIn(doc.In, rootRouter.openapi.Components.Schemas)
-	if componentSchema != nil {
-		for na, cs := range componentSchema {
-			if _, ex := rootRouter.openapi.Components.Schemas[na]; ex {
-				continue
+	inSchemas, requestBody := r.handleDocIn(doc.In)
+	if requestBody != nil {
+		op.RequestBody = requestBody
+	}
+
+	// the response schemas take precedence over request schemas of the same name
+	componentSchemas := make(map[string]Schema)
+	maps.Copy(componentSchemas, inSchemas)
+	maps.Copy(componentSchemas, outSchemas)
+
+	if r.inlineSchemas {
+		op, componentSchemas = inlineOperation(op, componentSchemas, rootRouter.openapi.Components.Schemas)
+	}
+
+	for na, cs := range componentSchemas {
+		if _, ex := rootRouter.openapi.Components.Schemas[na]; ex {
+			continue
+		}
+		rootRouter.openapi.Components.Schemas[na] = cs
+	}
+
+	// every operation needs at least one response to be valid
+	if len(op.Responses) == 0 && r.defaultResponse != nil {
+		op.Responses = map[string]Response{
+			r.defaultResponseCode: *r.defaultResponse,
+		}
+	}
+
+	if doc.ExampleProvider != nil {
+		responses := make(map[string]Response, len(op.Responses))
+		for code, resp := range op.Responses {
+			if example := doc.ExampleProvider(code); example != nil && len(resp.Content) > 0 {
+				content := make(map[string]MediaType, len(resp.Content))
+				for mt, media := range resp.Content {
+					media.Example = example
+					content[mt] = media
+				}
+				resp.Content = content
 			}
-			rootRouter.openapi.Components.Schemas[na] = cs
+			responses[code] = resp
 		}
+		op.Responses = responses
 	}
 
-	if requestBody != nil {
-		op.RequestBody = requestBody
+	if len(r.responseHeaders) > 0 {
+		responses := make(map[string]Response, len(op.Responses))
+		for code, resp := range op.Responses {
+			headers := maps.Clone(r.responseHeaders)
+			maps.Copy(headers, resp.Headers)
+			resp.Headers = headers
+			responses[code] = resp
+		}
+		op.Responses = responses
 	}
 
 	rootRouter.openapi.Paths[stripPattern] = pathItem.SetMethod(method, op)
@@ -357,58 +979,179 @@ func addIfMissing[T comparable](slice []T, element T, prepend bool) []T {
 	return append(slice, element)
 }
 
-func (r *Router) getMethodsForPattern(pattern string) []string {
+// getMethodsForPattern returns the methods documented on the path for the
+// host, as listed in the Allow header of its OPTIONS handler.
+func (r *Router) getMethodsForPattern(host, pattern string) []string {
 	rootRouter := r.rootParent()
 	rootRouter.mu.RLock()
 	defer rootRouter.mu.RUnlock()
 
-	var methods []string
-	if routeInfo, exists := rootRouter.openapi.Paths[pattern]; exists {
-		if routeInfo.Get != nil {
-			methods = addIfMissing(methods, http.MethodGet, false)
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+
+	path, ok := rootRouter.optionsPaths[host+pattern]
+	if !ok {
+		path, ok = rootRouter.optionsPaths[pattern]
+	}
+	if !ok {
+		return nil
+	}
+	return path.allow()
+}
+
+// serverMethods returns every method served by the router, including the HEAD
+// requests the mux answers for GET routes.
+func (r *Router) serverMethods() []string {
+	rootRouter := r.rootParent()
+	rootRouter.mu.RLock()
+	defer rootRouter.mu.RUnlock()
+
+	registered := map[string]bool{http.MethodOptions: true}
+	for _, rt := range rootRouter.routes {
+		if rt.method == "" {
+			continue // routes matching any method, such as proxies
 		}
-		if routeInfo.Post != nil {
-			methods = addIfMissing(methods, http.MethodPost, false)
+		registered[rt.method] = true
+		if rt.method == http.MethodGet {
+			registered[http.MethodHead] = true
 		}
-		if routeInfo.Put != nil {
-			methods = addIfMissing(methods, http.MethodPut, false)
+	}
+
+	return sortMethods(registered)
+}
+
+// AllowedMethods returns the methods registered for the path of the request,
+// including the HEAD requests the mux answers for GET routes. It is meant to be
+// plugged into middlewares that need the route table, such as the
+// AllowedMethodsFunc of the CORS middleware. It returns nil when the Mux does
+// not provide a Handler method to match requests without serving them.
+func (r *Router) AllowedMethods(req *http.Request) []string {
+	rootRouter := r.rootParent()
+	rootRouter.mu.RLock()
+	candidates := make(map[string]bool)
+	for _, rt := range rootRouter.routes {
+		if rt.method == "" {
+			continue // routes matching any method, such as proxies
 		}
-		if routeInfo.Delete != nil {
-			methods = addIfMissing(methods, http.MethodDelete, false)
+		if rt.method == http.MethodOptions && rootRouter.preflights[rt.host+rt.pattern] {
+			continue // answered by the CORS middleware, not a route of its own
 		}
-		if routeInfo.Patch != nil {
-			methods = addIfMissing(methods, http.MethodPatch, false)
+		candidates[rt.method] = true
+		if rt.method == http.MethodGet {
+			candidates[http.MethodHead] = true
 		}
 	}
-	return methods
+	notAllowed := maps.Clone(rootRouter.notAllowed)
+	rootRouter.mu.RUnlock()
+
+	matcher, ok := rootRouter.mux.(interface {
+		Handler(*http.Request) (http.Handler, string)
+	})
+	if !ok {
+		return nil
+	}
+
+	allowed := make(map[string]bool)
+	for m := range candidates {
+		probe := req.Clone(req.Context())
+		probe.Method = m
+		if _, pattern := matcher.Handler(probe); pattern != "" && !notAllowed[pattern] {
+			allowed[m] = true
+		}
+	}
+
+	return sortMethods(allowed)
+}
+
+// sortMethods lists the methods in their conventional order, followed by any
+// other method in alphabetical order.
+func sortMethods(set map[string]bool) []string {
+	var methods []string
+	for _, m := range []string{http.MethodOptions, http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete} {
+		if set[m] {
+			methods = append(methods, m)
+		}
+	}
+
+	others := make([]string, 0, len(set))
+	for m := range set {
+		if !slices.Contains(methods, m) {
+			others = append(others, m)
+		}
+	}
+	slices.Sort(others)
+
+	return append(methods, others...)
+}
+
+// optionsPath is a path documented on a host, or on every host when host is
+// empty, along with what its OPTIONS handler needs.
+type optionsPath struct {
+	host        string
+	pattern     string
+	methods     []string     // documented methods, HEAD included for AutoHead routes
+	middlewares []Middleware // middlewares of the first route documented on the path
+}
+
+// allow lists the methods of the path for the Allow header, both of its
+// OPTIONS handler and of the responses to methods it does not handle.
+func (p *optionsPath) allow() []string {
+	if len(p.methods) == 0 {
+		return nil
+	}
+
+	set := map[string]bool{http.MethodOptions: true}
+	for _, m := range p.methods {
+		set[m] = true
+	}
+	return sortMethods(set)
 }
 
-func (r *Router) registerOptionsHandler(strippedPattern string) {
+func (r *Router) registerOptionsHandler(path *optionsPath) {
 	rootRouter := r.rootParent()
 	rootRouter.mu.Lock()
 	defer rootRouter.mu.Unlock()
 
-	// Get the original pattern
-	pattern, exists := rootRouter.patternMap[strippedPattern]
-	if !exists {
-		pattern = strippedPattern // Fallback to strippedPattern if mapping is missing
+	// Paths of UseCORS routers already have their OPTIONS handler
+	if rootRouter.preflights[path.host+path.pattern] {
+		return
 	}
 
-	// Get methods for the pattern
-	routeInfo, exists := rootRouter.openapi.Paths[pattern]
-	if !exists || len(routeInfo.Methods()) == 0 {
+	methods := path.allow()
+	if len(methods) == 0 {
 		return
 	}
 
 	// Create the OPTIONS handler with the Allow header
-	methods := addIfMissing(routeInfo.Methods(), http.MethodOptions, true)
-	optionsHandler := func(w http.ResponseWriter, req *http.Request) {
-		w.Header().Set("Allow", strings.Join(methods, ", "))
-		w.WriteHeader(http.StatusNoContent)
+	var optionsHandler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		rootRouter.writeOptions(w, methods)
+	})
+
+	// Wrap it like the routes of the path, so e.g. CORS answers preflights
+	for i := len(path.middlewares) - 1; i >= 0; i-- {
+		optionsHandler = path.middlewares[i](optionsHandler)
 	}
 
 	// Register the handler
-	rootRouter.mux.HandleFunc("OPTIONS "+pattern, optionsHandler)
+	rootRouter.mux.Handle("OPTIONS "+path.host+path.pattern, markMatched(optionsHandler))
+}
+
+// writeOptions answers an OPTIONS request with the allowed methods, using the
+// configured status and optional JSON body.
+func (r *Router) writeOptions(w http.ResponseWriter, methods []string) {
+	w.Header().Set("Allow", strings.Join(methods, ", "))
+
+	if !r.optionsBody || r.optionsStatus == http.StatusNoContent {
+		w.WriteHeader(r.optionsStatus)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(r.optionsStatus)
+	_ = json.NewEncoder(w).Encode(struct {
+		Allow []string `json:"allow"`
+	}{methods})
 }
 
 func (r *Router) OperationID(s string) string {
@@ -427,7 +1170,61 @@ func (r *Router) OperationID(s string) string {
 	return strings.Join(parts, "")
 }
 
-func (r *Router) handleDocOut(do map[string]DocOut, schemas map[string]Schema) (map[string]Schema, map[string]Response) {
+// pathTag returns the first static segment of a path, skipping wildcards.
+func pathTag(pattern string) string {
+	for _, part := range strings.Split(pattern, "/") {
+		if part != "" && !strings.HasPrefix(part, "{") {
+			return part
+		}
+	}
+	return ""
+}
+
+// handlerName returns the declared name of a handler function, or an empty
+// string for anonymous functions.
+func handlerName(handler http.HandlerFunc) string {
+	fn := runtime.FuncForPC(reflect.ValueOf(handler).Pointer())
+	if fn == nil {
+		return ""
+	}
+
+	name := strings.TrimSuffix(fn.Name(), "-fm") // method values
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+
+	// closures are named func1, func2, ... or just numbered when nested
+	if rest := strings.TrimRight(name, "0123456789"); rest == "" || rest == "func" {
+		return ""
+	}
+
+	return name
+}
+
+// humanize turns a camelCase identifier into space separated title case words,
+// e.g. userHandler becomes User Handler.
+func humanize(s string) string {
+	var (
+		b     strings.Builder
+		runes = []rune(s)
+	)
+
+	for i, c := range runes {
+		if i > 0 && unicode.IsUpper(c) {
+			// start a new word unless inside an acronym such as ID or HTTP
+			if !unicode.IsUpper(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+				b.WriteByte(' ')
+			}
+		}
+		if i == 0 {
+			c = unicode.ToUpper(c)
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
+
+func (r *Router) handleDocOut(do map[string]DocOut) (map[string]Schema, map[string]Response) {
 	var (
 		componentSchemas map[string]Schema
 		routeResponse    map[string]Response
@@ -440,100 +1237,49 @@ func (r *Router) handleDocOut(do map[string]DocOut, schemas map[string]Schema) (
 	for responseCode, docOut := range do {
 		var schema *Schema
 		if docOut.Object != nil {
-			obj := reflect.ValueOf(docOut.Object)
-			if obj.Kind() == reflect.Ptr {
-				obj = obj.Elem()
-			}
-
-			pType := "object"
-			name := obj.Type().Name()
-			schema = &Schema{
-				Ref: fmt.Sprintf("#/components/schemas/%s", name),
+			if componentSchemas == nil {
+				componentSchemas = make(map[string]Schema)
 			}
 
-			if _, ok := schemas[name]; !ok {
-				if obj.Kind() == reflect.Slice {
-					pType = "array"
-					elementType := obj.Type().Elem()
-					obj = reflect.New(elementType).Elem()
-					name = obj.Type().Name()
-					schema = &Schema{
-						Type: pType,
-						Items: &Schema{
-							Ref: fmt.Sprintf("#/components/schemas/%s", name),
-						},
-					}
-				}
-
-				properties := make(map[string]Schema)
-
-				for i := 0; i < obj.NumField(); i++ {
-					fieldType := obj.Type().Field(i)
-					fieldName := fieldType.Name
-					jsonTag := fieldType.Tag.Get("json")
-					if jsonTag != "" && jsonTag != "-" {
-						fieldName = strings.Split(jsonTag, ",")[0]
-					}
-
-					fieldKind := fieldType.Type.Kind()
-					var typeName string
-					switch fieldKind {
-					case reflect.String:
-						typeName = "string"
-					case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-						typeName = "integer"
-					case reflect.Float32, reflect.Float64:
-						typeName = "number"
-					case reflect.Bool:
-						typeName = "boolean"
-					case reflect.Struct:
-						typeName = "object"
-					case reflect.Slice, reflect.Array:
-						typeName = "array"
-					default:
-						typeName = "string" // Default to string if unknown
-					}
-
-					properties[fieldName] = Schema{
-						Type: typeName,
-					}
-				}
-
-				if componentSchemas == nil {
-					componentSchemas = make(map[string]Schema)
-				}
-
-				componentSchemas[name] = Schema{
-					Type:       pType,
-					Properties: properties,
-				}
-			}
-		} else {
-			// Handle nil docOut.Object by setting schema to nil
-			schema = nil
+			// named structs are referenced, slices describe their items and
+			// other types map to their kind, just like struct fields
+			s := r.fieldSchema(reflect.TypeOf(docOut.Object), componentSchemas)
+			schema = &s
 		}
 
 		if routeResponse == nil {
 			routeResponse = make(map[string]Response)
 		}
 
-		mediaType := MediaType{}
+		mediaType := MediaType{Example: docOut.Example}
 		if schema != nil {
 			mediaType.Schema = schema
 		}
 
+		content := map[string]MediaType{
+			docOut.ApplicationType: mediaType,
+		}
+		if docOut.ApplicationType == "" && schema == nil {
+			content = nil // a response without body
+		} else if docOut.ApplicationType == "" && len(r.produces) > 0 {
+			content = make(map[string]MediaType, len(r.produces))
+			for _, mt := range r.produces {
+				content[mt] = mediaType
+			}
+		}
+
 		routeResponse[responseCode] = Response{
 			Description: docOut.Description,
-			Content: map[string]MediaType{
-				docOut.ApplicationType: mediaType,
-			},
+			Content:     content,
+			Headers:     docOut.Headers,
+			Links:       docOut.Links,
 		}
 	}
 
 	return componentSchemas, routeResponse
 }
 
-func (r *Router) handleDocIn(do map[string]DocIn, schemas map[string]Schema) (map[string]Schema, *RequestBody) {
+func (r *Router) handleDocIn(do map[string]DocIn) (map[string]Schema, *RequestBody) {
 	var (
 		componentSchemas map[string]Schema
 		requestBody      *RequestBody
@@ -544,33 +1290,13 @@ func (r *Router) handleDocIn(do map[string]DocIn, schemas map[string]Schema) (ma
 	}
 
 	for contentType, docIn := range do {
-		obj := reflect.ValueOf(docIn.Object)
-		if obj.Kind() == reflect.Ptr {
-			obj = obj.Elem()
+		if componentSchemas == nil {
+			componentSchemas = make(map[string]Schema)
 		}
 
-		name := obj.Type().Name()
-		if _, ok := schemas[name]; !ok {
-			properties := make(map[string]Schema)
-			for i := 0; i < obj.NumField(); i++ {
-				field := obj.Field(i)
-				fieldName := obj.Type().Field(i).Name
-				fieldType := field.Type().Name()
-
-				properties[fieldName] = Schema{
-					Type: fieldType,
-				}
-			}
-
-			if componentSchemas == nil {
-				componentSchemas = make(map[string]Schema)
-			}
-
-			componentSchemas[name] = Schema{
-				Type:       "object",
-				Properties: properties,
-			}
-		}
+		// request bodies are described like responses, named structs are
+		// referenced and their fields mapped to OpenAPI types
+		schema := r.fieldSchema(reflect.TypeOf(docIn.Object), componentSchemas)
 
 		if requestBody == nil {
 			requestBody = &RequestBody{
@@ -578,18 +1304,72 @@ func (r *Router) handleDocIn(do map[string]DocIn, schemas map[string]Schema) (ma
 			}
 		}
 
-		requestBody.Content[contentType] = MediaType{
-			Schema: &Schema{
-				Ref: fmt.Sprintf("#/components/schemas/%s", name),
-			},
+		mediaType := MediaType{
+			Schema:   &schema,
+			Example:  docIn.Example,
+			Encoding: docIn.Encoding,
 		}
+
+		if contentType == "" && len(r.consumes) > 0 {
+			for _, mt := range r.consumes {
+				requestBody.Content[mt] = mediaType
+			}
+			continue
+		}
+		requestBody.Content[contentType] = mediaType
 	}
 
 	return componentSchemas, requestBody
 }
 
-// OpenAPI returns the root documentation tree
+// OpenAPI returns the root documentation tree. When hooks are registered with
+// OnOpenAPI, a copy of the tree is returned with the hooks applied.
 func (r *Router) OpenAPI() *OpenAPI {
 	rootRouter := r.rootParent()
-	return rootRouter.openapi
+
+	rootRouter.mu.RLock()
+	defer rootRouter.mu.RUnlock()
+
+	if len(rootRouter.openapiHooks) == 0 {
+		return rootRouter.openapi
+	}
+
+	doc := rootRouter.openapi.Clone()
+	for _, hook := range rootRouter.openapiHooks {
+		hook(doc)
+	}
+	return doc
+}
+
+// ResetDocs clears the documented paths and component schemas, as well as the
+// summaries and tags returned by GetDocs and Routes, e.g. between table driven
+// documentation tests. The handlers mounted on the mux are not removed: the
+// routes keep being served, with their policies, and listed by Routes.
+func (r *Router) ResetDocs() {
+	rootRouter := r.rootParent()
+
+	rootRouter.mu.Lock()
+	defer rootRouter.mu.Unlock()
+
+	if rootRouter.routeDocs != nil {
+		rootRouter.routeDocs = make(map[string]RouteDoc)
+	}
+
+	rootRouter.openapi.Paths = make(map[string]PathItem)
+	rootRouter.openapi.Components.Schemas = make(map[string]Schema)
+	rootRouter.componentTypes = nil
+	rootRouter.patternMap = make(map[string]string)
+	rootRouter.optionsPaths = nil
+}
+
+// OnOpenAPI registers a hook that post-processes the document returned by
+// OpenAPI, e.g. to inject vendor extensions. Hooks run in registration order on
+// a copy, so they never alter the documentation the routes registered.
+func (r *Router) OnOpenAPI(hook func(*OpenAPI)) {
+	rootRouter := r.rootParent()
+
+	rootRouter.mu.Lock()
+	defer rootRouter.mu.Unlock()
+
+	rootRouter.openapiHooks = append(rootRouter.openapiHooks, hook)
 }
@@ -0,0 +1,129 @@
+package router
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestQueryDefaults(t *testing.T) {
+	mux := http.NewServeMux()
+	r := New(mux, "Example API", "1.0.0")
+	r.UseOpenapiDocs(true)
+
+	type listQuery struct {
+		Page   int      `query:"page"`
+		Search string   `query:"q"`
+		Tags   []string `query:"tag"`
+	}
+
+	r.Get("/users", func(w http.ResponseWriter, req *http.Request) {
+		var q listQuery
+		if err := BindQuery(req, &q); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		_, _ = fmt.Fprintf(w, "page=%d q=%s tags=%v", q.Page, q.Search, q.Tags)
+	}, Docs{
+		Parameters: []Parameter{
+			{
+				Name: "page",
+				In:   "query",
+				Schema: &Schema{
+					Type:    "integer",
+					Default: 1,
+				},
+			},
+		},
+	})
+
+	testCases := []struct {
+		name   string
+		url    string
+		status int
+		body   string
+	}{
+		{"Omitted param yields default", "/users?q=bob", http.StatusOK, "page=1 q=bob tags=[]"},
+		{"Given param wins", "/users?page=3&tag=a&tag=b", http.StatusOK, "page=3 q= tags=[a b]"},
+		{"Invalid param", "/users?page=x", http.StatusBadRequest, "router: query \"page\": strconv.ParseInt: parsing \"x\": invalid syntax\n"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tc.url, nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tc.status || w.Body.String() != tc.body {
+				t.Errorf("Expected %d %q, got %d %q", tc.status, tc.body, w.Code, w.Body.String())
+			}
+		})
+	}
+
+	param := r.OpenAPI().Paths["/users"].Get.Parameters[0]
+	if param.Schema.Default != 1 {
+		t.Errorf("Expected documented default 1, got %v", param.Schema.Default)
+	}
+}
+
+func TestBindFormMultipart(t *testing.T) {
+	mux := http.NewServeMux()
+	r := New(mux, "Example API", "1.0.0")
+	r.SetMaxMultipartMemory(1024)
+
+	content := strings.Repeat("0123456789", 1000) // 10KB, above the threshold
+
+	var tmpName string
+	r.Post("/uploads", func(w http.ResponseWriter, req *http.Request) {
+		var form struct {
+			Title string                `form:"title"`
+			File  *multipart.FileHeader `form:"file"`
+		}
+		if err := BindForm(req, &form); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		f, err := form.File.Open()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		defer f.Close()
+
+		if osFile, ok := f.(*os.File); ok {
+			tmpName = osFile.Name()
+		}
+
+		b, _ := io.ReadAll(f)
+		_, _ = fmt.Fprintf(w, "%s %s %d %v", form.Title, form.File.Filename, len(b), string(b) == content)
+	})
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	_ = mw.WriteField("title", "report")
+	fw, _ := mw.CreateFormFile("file", "report.txt")
+	_, _ = fw.Write([]byte(content))
+	_ = mw.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/uploads", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	if want := "report report.txt 10000 true"; rr.Body.String() != want {
+		t.Errorf("Expected %q, got %q", want, rr.Body.String())
+	}
+	if tmpName == "" {
+		t.Fatal("Expected the file to be stored in a temporary file")
+	}
+	if _, err := os.Stat(tmpName); !os.IsNotExist(err) {
+		t.Errorf("Expected the temporary file to be removed, got %v", err)
+	}
+}
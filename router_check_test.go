@@ -0,0 +1,64 @@
+package router
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestCheck(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {}
+
+	t.Run("Valid router", func(t *testing.T) {
+		mux := http.NewServeMux()
+		r := New(mux, "Example API", "1.0.0")
+		r.UseOpenapiDocs(true)
+
+		r.Get("/users/{id}", handler).Response(http.StatusOK, UserCreated{})
+		r.Get("/files/{path...}", handler, Docs{Summary: "Files"})
+
+		if err := r.Check(); err != nil {
+			t.Errorf("Expected no errors, got %v", err)
+		}
+	})
+
+	t.Run("Misconfigured router", func(t *testing.T) {
+		r := New(exactMux{}, "Example API", "1.0.0")
+		r.UseOpenapiDocs(true)
+		r.UseDefaultResponse("", nil)
+
+		r.Get("/users/{id", handler)
+		r.Get("/users", handler, Docs{OperationID: "listUsers", Responses: map[string]Response{"200": {Description: "OK"}}})
+		r.Get("/users", handler, Docs{OperationID: "listUsers", Responses: map[string]Response{"200": {Description: "OK"}}})
+		r.Post("/accounts", handler, Docs{OperationID: "listUsers", Responses: map[string]Response{
+			"200": {
+				Description: "OK",
+				Content: map[string]MediaType{
+					"application/json": {Schema: &Schema{Ref: "#/components/schemas/Missing"}},
+				},
+			},
+		}})
+		r.Delete("/accounts", handler, Docs{Summary: "No responses"})
+		r.OnOpenAPI(func(doc *OpenAPI) {
+			doc.Components.Schemas["Page[User]"] = Schema{Type: "object"}
+		})
+
+		err := r.Check()
+		if err == nil {
+			t.Fatal("Expected errors")
+		}
+
+		for _, expected := range []string{
+			`router: route GET /users/{id: wildcard "{id" must be a full path segment`,
+			"router: duplicate route GET /users",
+			`router: duplicate operationId "listUsers" on POST /accounts and GET /users`,
+			"router: POST /accounts references undefined schema #/components/schemas/Missing",
+			`router: $.paths["/accounts"].delete.responses: field is required`,
+			`router: schema name "Page[User]" must match ^[a-zA-Z0-9._-]+$`,
+		} {
+			if !strings.Contains(err.Error(), expected) {
+				t.Errorf("Expected %q in\n%v", expected, err)
+			}
+		}
+	})
+}
@@ -221,3 +221,150 @@ func TestCORSMiddleware(t *testing.T) {
 		})
 	}
 }
+
+func TestCORSAutoOptions(t *testing.T) {
+	mux := http.NewServeMux()
+	r := New(mux, "Example API", "1.0.0")
+	r.UseOpenapiDocs(true)
+
+	handler := func(w http.ResponseWriter, r *http.Request) {}
+	r.Group("/api", func(api *Router) {
+		api.Use(middleware.CORS(middleware.CORSOptions{
+			AllowedOrigins:     []string{"https://example.com"},
+			AllowedHeaders:     []string{"Content-Type"},
+			AllowedMethodsFunc: r.AllowedMethods,
+		}))
+		api.Get("/users", handler, Docs{Summary: "List users"})
+		api.Post("/users", handler, Docs{Summary: "Create user"})
+	})
+	r.Get("/health", handler, Docs{Summary: "Health"})
+
+	req := httptest.NewRequest(http.MethodOptions, "/api/users", nil)
+	req.Header.Set("Origin", "https://example.com")
+	req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusNoContent {
+		t.Errorf("Expected status 204, got %d", rr.Code)
+	}
+	if got := rr.Header().Get("Access-Control-Allow-Origin"); got != "https://example.com" {
+		t.Errorf("Expected Access-Control-Allow-Origin on the preflight, got %q", got)
+	}
+	if got := rr.Header().Get("Access-Control-Allow-Methods"); got != "GET, HEAD, POST" {
+		t.Errorf("Expected the route methods in Access-Control-Allow-Methods, got %q", got)
+	}
+
+	// paths registered outside the group are not wrapped by its middlewares
+	req = httptest.NewRequest(http.MethodOptions, "/health", nil)
+	req.Header.Set("Origin", "https://example.com")
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	if got := rr.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Errorf("Expected no CORS headers outside the group, got %q", got)
+	}
+	if got := rr.Header().Get("Allow"); got != "OPTIONS, GET" {
+		t.Errorf("Expected Allow %q, got %q", "OPTIONS, GET", got)
+	}
+}
+
+func TestUseCORSPerGroup(t *testing.T) {
+	for _, docs := range []bool{false, true} {
+		mux := http.NewServeMux()
+		r := New(mux, "Example API", "1.0.0")
+		r.UseOpenapiDocs(docs)
+
+		handler := func(w http.ResponseWriter, r *http.Request) {}
+		r.Group("/public", func(public *Router) {
+			public.UseCORS(middleware.CORSOptions{AllowedOrigins: []string{"*"}})
+			public.Get("/posts", handler, Docs{Summary: "List posts"})
+		})
+		r.Group("/api", func(api *Router) {
+			api.UseCORS(middleware.CORSOptions{
+				AllowedOrigins:   []string{"https://app.example.com"},
+				AllowCredentials: true,
+			})
+			api.Get("/users", handler, Docs{Summary: "List users"})
+			api.Post("/users", handler, Docs{Summary: "Create user"})
+		})
+
+		tests := []struct {
+			path        string
+			origin      string
+			status      int
+			allowOrigin string
+			methods     string
+		}{
+			{"/public/posts", "https://any.example.org", http.StatusNoContent, "*", "GET, HEAD"},
+			{"/api/users", "https://app.example.com", http.StatusNoContent, "https://app.example.com", "GET, HEAD, POST"},
+			{"/api/users", "https://any.example.org", http.StatusForbidden, "", ""},
+		}
+
+		for _, tt := range tests {
+			req := httptest.NewRequest(http.MethodOptions, tt.path, nil)
+			req.Header.Set("Origin", tt.origin)
+			req.Header.Set("Access-Control-Request-Method", http.MethodGet)
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, req)
+
+			if rr.Code != tt.status {
+				t.Errorf("docs %v, %s from %s: Expected status %d, got %d", docs, tt.path, tt.origin, tt.status, rr.Code)
+			}
+			if got := rr.Header().Get("Access-Control-Allow-Origin"); got != tt.allowOrigin {
+				t.Errorf("docs %v, %s from %s: Expected Access-Control-Allow-Origin %q, got %q", docs, tt.path, tt.origin, tt.allowOrigin, got)
+			}
+			if got := rr.Header().Get("Access-Control-Allow-Methods"); got != tt.methods {
+				t.Errorf("docs %v, %s from %s: Expected Access-Control-Allow-Methods %q, got %q", docs, tt.path, tt.origin, tt.methods, got)
+			}
+		}
+
+		// a plain OPTIONS request is answered with the allowed methods
+		req := httptest.NewRequest(http.MethodOptions, "/api/users", nil)
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+
+		if got := rr.Header().Get("Allow"); got != "OPTIONS, GET, HEAD, POST" {
+			t.Errorf("docs %v: Expected Allow %q, got %q", docs, "OPTIONS, GET, HEAD, POST", got)
+		}
+	}
+}
+
+func TestUseCORSMerge(t *testing.T) {
+	for _, docs := range []bool{false, true} {
+		mux := http.NewServeMux()
+		r := New(mux, "Example API", "1.0.0")
+		r.UseOpenapiDocs(docs)
+
+		corsRouter := New(http.NewServeMux(), "Plugin", "1.0.0")
+		corsRouter.UseOpenapiDocs(docs)
+		corsRouter.UseCORS(middleware.CORSOptions{AllowedOrigins: []string{"https://app.example.com"}})
+		corsRouter.Get("/x", func(w http.ResponseWriter, r *http.Request) {}, Docs{Summary: "Get x"})
+
+		r.Merge("/api", corsRouter)
+
+		req := httptest.NewRequest(http.MethodOptions, "/api/x", nil)
+		req.Header.Set("Origin", "https://app.example.com")
+		req.Header.Set("Access-Control-Request-Method", http.MethodGet)
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusNoContent {
+			t.Errorf("docs %v: Expected status %d, got %d", docs, http.StatusNoContent, rr.Code)
+		}
+		if got := rr.Header().Get("Access-Control-Allow-Origin"); got != "https://app.example.com" {
+			t.Errorf("docs %v: Expected Access-Control-Allow-Origin %q, got %q", docs, "https://app.example.com", got)
+		}
+		if got := rr.Header().Get("Access-Control-Allow-Methods"); got != "GET, HEAD" {
+			t.Errorf("docs %v: Expected Access-Control-Allow-Methods %q, got %q", docs, "GET, HEAD", got)
+		}
+
+		var preflight bool
+		for _, route := range r.Routes() {
+			preflight = preflight || route.Method == http.MethodOptions && route.Pattern == "/api/x"
+		}
+		if !preflight {
+			t.Errorf("docs %v: Expected the preflight route in the route table, got %+v", docs, r.Routes())
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package router
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDiffOpenAPI(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {}
+	search := Parameter{Name: "q", In: "query", Schema: &Schema{Type: "string"}}
+
+	before := New(http.NewServeMux(), "Example API", "1.0.0")
+	before.UseOpenapiDocs(true)
+	{
+		type User struct {
+			ID       int    `json:"id"`
+			Name     string `json:"name"`
+			Nickname string `json:"nickname"`
+		}
+		before.Get("/users", handler, Docs{Parameters: []Parameter{search}}).
+			Response(http.StatusOK, User{}).
+			Response(http.StatusNotFound, nil)
+		before.Delete("/users/{id}", handler, Docs{})
+	}
+
+	search.Required = true
+	after := New(http.NewServeMux(), "Example API", "1.0.0")
+	after.UseOpenapiDocs(true)
+	{
+		type User struct {
+			ID    string `json:"id"`
+			Name  string `json:"name" openapi:"required"`
+			Email string `json:"email"`
+		}
+		after.Get("/users", handler, Docs{Parameters: []Parameter{search}}).
+			Response(http.StatusOK, User{})
+		after.Post("/users", handler, Docs{})
+	}
+
+	var got []string
+	for _, change := range DiffOpenAPI(before.OpenAPI(), after.OpenAPI()) {
+		got = append(got, change.String())
+	}
+
+	want := []string{
+		"added #/components/schemas/User/properties/email: optional property added",
+		"changed #/components/schemas/User/properties/id: type changed from integer to string (breaking)",
+		"changed #/components/schemas/User/properties/name: property became required (breaking)",
+		"removed #/components/schemas/User/properties/nickname: property removed (breaking)",
+		"removed DELETE /users/{id}: operation removed (breaking)",
+		"changed GET /users parameter query q: parameter became required (breaking)",
+		"removed GET /users response 404: response removed (breaking)",
+		"added POST /users: operation added",
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Unexpected changes:\n%s", strings.Join(got, "\n"))
+	}
+
+	if changes := DiffOpenAPI(before.OpenAPI(), before.OpenAPI()); len(changes) != 0 {
+		t.Errorf("Expected no changes between identical documents, got %v", changes)
+	}
+
+	ref := Schema{Ref: "#/components/schemas/User"}
+	nullable := Schema{AllOf: []Schema{ref}, Nullable: true}
+	if changes := DiffOpenAPI(
+		&OpenAPI{Components: Components{Schemas: map[string]Schema{"Team": {Type: "object", Properties: map[string]Schema{"lead": ref}}}}},
+		&OpenAPI{Components: Components{Schemas: map[string]Schema{"Team": {Type: "object", Properties: map[string]Schema{"lead": nullable}}}}},
+	); len(changes) != 0 {
+		t.Errorf("Expected a reference becoming nullable not to change its type, got %v", changes)
+	}
+}
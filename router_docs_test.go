@@ -0,0 +1,254 @@
+package router
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/donseba/go-router/middleware"
+)
+
+func TestServeOpenAPI(t *testing.T) {
+	mux := http.NewServeMux()
+	r := New(mux, "Example API", "1.0.0")
+	r.UseOpenapiDocs(true)
+
+	requireToken := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			if req.Header.Get("Authorization") != "Bearer docs" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			next.ServeHTTP(w, req)
+		})
+	}
+
+	r.Get("/users", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}, Docs{Summary: "User List"})
+
+	r.ServeOpenAPI("/openapi.json", requireToken)
+
+	testCases := []struct {
+		name   string
+		path   string
+		token  string
+		status int
+	}{
+		{"Docs without credentials", "/openapi.json", "", http.StatusUnauthorized},
+		{"Docs with credentials", "/openapi.json", "Bearer docs", http.StatusOK},
+		{"API route is not protected", "/users", "", http.StatusOK},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			if tc.token != "" {
+				req.Header.Set("Authorization", tc.token)
+			}
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tc.status {
+				t.Errorf("Expected status %d, got %d", tc.status, w.Code)
+			}
+
+			if tc.path == "/openapi.json" && w.Code == http.StatusOK {
+				var doc OpenAPI
+				if err := json.Unmarshal(w.Body.Bytes(), &doc); err != nil {
+					t.Fatal(err)
+				}
+				if doc.Paths["/users"].Get == nil {
+					t.Errorf("Expected the served document to contain /users, got %s", w.Body.String())
+				}
+			}
+		})
+	}
+}
+
+func TestUseForAssetsCompress(t *testing.T) {
+	mux := http.NewServeMux()
+	r := New(mux, "Example API", "1.0.0")
+	r.UseOpenapiDocs(true)
+	r.Use(middleware.ContentLengthMiddleware)
+	r.UseForAssets(middleware.Compress)
+
+	r.Get("/users", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("users"))
+	}, Docs{Summary: "User List"})
+
+	r.ServeOpenAPI("/openapi.json")
+
+	req := httptest.NewRequest(http.MethodGet, "/openapi.json", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if enc := w.Header().Get("Content-Encoding"); enc != "gzip" {
+		t.Fatalf("Expected gzip encoded spec, got Content-Encoding %q", enc)
+	}
+	if cl := w.Header().Get("Content-Length"); cl != strconv.Itoa(w.Body.Len()) {
+		t.Errorf("Expected Content-Length %d of the compressed body, got %q", w.Body.Len(), cl)
+	}
+
+	gz, err := gzip.NewReader(w.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc OpenAPI
+	if err := json.NewDecoder(gz).Decode(&doc); err != nil {
+		t.Fatalf("Failed to decode the spec: %v", err)
+	}
+	if doc.Paths["/users"].Get == nil {
+		t.Error("Expected the decoded spec to document GET /users")
+	}
+
+	// API routes are not compressed
+	req = httptest.NewRequest(http.MethodGet, "/users", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if enc := w.Header().Get("Content-Encoding"); enc != "" {
+		t.Errorf("Expected uncompressed API response, got Content-Encoding %q", enc)
+	}
+}
+
+func TestHandleRouteTable(t *testing.T) {
+	mux := http.NewServeMux()
+	r := New(mux, "Example API", "1.0.0")
+	r.UseOpenapiDocs(true)
+
+	handler := func(w http.ResponseWriter, r *http.Request) {}
+	r.Get("/users", handler, Docs{Tags: []string{"users"}})
+	r.Group("/admin", func(admin *Router) {
+		admin.Delete("/users/{id}", handler)
+	})
+	r.HandleRouteTable("/_routes.json")
+
+	req := httptest.NewRequest(http.MethodGet, "/_routes.json", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	var routes []struct {
+		Method  string   `json:"method"`
+		Pattern string   `json:"pattern"`
+		Tags    []string `json:"tags"`
+		HasDocs bool     `json:"hasDocs"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&routes); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(routes) != 3 {
+		t.Fatalf("Expected 3 routes, got %d: %+v", len(routes), routes)
+	}
+	if rt := routes[0]; rt.Method != http.MethodGet || rt.Pattern != "/users" || !rt.HasDocs || len(rt.Tags) != 1 || rt.Tags[0] != "users" {
+		t.Errorf("Unexpected documented route %+v", rt)
+	}
+	if rt := routes[1]; rt.Method != http.MethodDelete || rt.Pattern != "/admin/users/{id}" || rt.HasDocs {
+		t.Errorf("Unexpected undocumented route %+v", rt)
+	}
+	if rt := routes[2]; rt.Method != http.MethodGet || rt.Pattern != "/_routes.json" {
+		t.Errorf("Expected the route table itself to be listed, got %+v", rt)
+	}
+}
+
+func TestServeAPIExplorer(t *testing.T) {
+	mux := http.NewServeMux()
+	r := New(mux, "Example API", "1.0.0")
+	r.UseOpenapiDocs(true)
+
+	r.Get("/users", func(w http.ResponseWriter, r *http.Request) {}, Docs{Summary: "List users"})
+	r.Group("/api", func(api *Router) {
+		api.ServeAPIExplorer("/docs", func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				w.Header().Set("X-Docs", "1")
+				next.ServeHTTP(w, req)
+			})
+		})
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/docs", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/html") {
+		t.Errorf("Expected an HTML page, got %q", ct)
+	}
+	if w.Header().Get("X-Docs") != "1" {
+		t.Error("Expected the route middleware to wrap the page")
+	}
+	body := w.Body.String()
+	if !strings.Contains(body, `"/api/docs/openapi.json"`) || !strings.Contains(body, "api-explorer") {
+		t.Errorf("Expected an explorer page pointing at the spec, got %s", body)
+	}
+	if strings.Contains(body, "https://") {
+		t.Error("Expected no assets loaded from a CDN")
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/docs/openapi.json", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	var doc OpenAPI
+	if err := json.NewDecoder(w.Body).Decode(&doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc.Paths["/users"].Get == nil {
+		t.Error("Expected the spec to document GET /users")
+	}
+	if w.Header().Get("X-Docs") != "1" {
+		t.Error("Expected the route middleware to wrap the spec")
+	}
+}
+
+func TestServeAPIReference(t *testing.T) {
+	mux := http.NewServeMux()
+	r := New(mux, "Example API", "1.0.0")
+	r.UseOpenapiDocs(true)
+
+	r.Get("/users", func(w http.ResponseWriter, r *http.Request) {}, Docs{Summary: "List users"})
+	r.Group("/api", func(api *Router) {
+		api.ServeAPIReference("/reference", "")
+		api.ServeAPIReference("/public-reference", "/specs/public.json")
+	})
+
+	for path, specURL := range map[string]string{
+		"/api/reference":        `"/api/reference/openapi.json"`,
+		"/api/public-reference": `"/specs/public.json"`,
+	} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: Expected status 200, got %d", path, w.Code)
+		}
+		if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/html") {
+			t.Errorf("%s: Expected an HTML page, got %q", path, ct)
+		}
+		body := w.Body.String()
+		if !strings.Contains(body, "APIReference.init("+specURL) {
+			t.Errorf("%s: Expected the reference init script loading %s, got %s", path, specURL, body)
+		}
+		if strings.Contains(body, "https://") {
+			t.Errorf("%s: Expected no assets loaded from a CDN", path)
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/reference/openapi.json", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected the spec to be served, got %d", w.Code)
+	}
+}
@@ -0,0 +1,73 @@
+package router
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRespond(t *testing.T) {
+	type message struct {
+		XMLName xml.Name `json:"-" xml:"message"`
+		Text    string   `json:"text" xml:"text"`
+	}
+
+	newRouter := func(defaultType string) (*Router, *error) {
+		r := New(http.NewServeMux(), "Example API", "1.0.0")
+		r.RegisterEncoder("application/xml", func(w io.Writer, v any) error {
+			return xml.NewEncoder(w).Encode(v)
+		})
+		if defaultType != "" {
+			r.SetDefaultEncoder(defaultType)
+		}
+
+		var respondErr error
+		r.Get("/message", func(w http.ResponseWriter, req *http.Request) {
+			respondErr = Respond(w, req, http.StatusOK, message{Text: "hi"})
+		})
+		return r, &respondErr
+	}
+
+	tests := []struct {
+		name        string
+		defaultType string
+		accept      string
+		status      int
+		contentType string
+		body        string
+		err         error
+	}{
+		{"Registered encoder", "", "application/xml", http.StatusOK, "application/xml", "<message><text>hi</text></message>", nil},
+		{"Quality ordering", "", "application/xml;q=0.5, application/json", http.StatusOK, "application/json", `{"text":"hi"}` + "\n", nil},
+		{"Unmatched without default", "", "text/csv", http.StatusNotAcceptable, "text/plain; charset=utf-8", "Not Acceptable\n", ErrNotAcceptable},
+		{"Unmatched with default", "application/json", "text/csv", http.StatusOK, "application/json", `{"text":"hi"}` + "\n", nil},
+		{"Wildcard uses default", "application/xml", "*/*", http.StatusOK, "application/xml", "<message><text>hi</text></message>", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, respondErr := newRouter(tt.defaultType)
+
+			req := httptest.NewRequest(http.MethodGet, "/message", nil)
+			req.Header.Set("Accept", tt.accept)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.status {
+				t.Errorf("Expected status %d, got %d", tt.status, w.Code)
+			}
+			if ct := w.Header().Get("Content-Type"); ct != tt.contentType {
+				t.Errorf("Expected Content-Type %q, got %q", tt.contentType, ct)
+			}
+			if w.Body.String() != tt.body {
+				t.Errorf("Expected body %q, got %q", tt.body, w.Body.String())
+			}
+			if !errors.Is(*respondErr, tt.err) {
+				t.Errorf("Expected error %v, got %v", tt.err, *respondErr)
+			}
+		})
+	}
+}
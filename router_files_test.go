@@ -0,0 +1,56 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestServeFile(t *testing.T) {
+	dir := t.TempDir()
+
+	fallback := filepath.Join(dir, "fallback.txt")
+	if err := os.WriteFile(fallback, []byte("fallback"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Validate missing file", func(t *testing.T) {
+		mux := http.NewServeMux()
+		r := New(mux, "Example API", "1.0.0")
+
+		err := r.ServeFile("/missing.txt", filepath.Join(dir, "missing.txt"), ServeFileOptions{Validate: true})
+		if err == nil {
+			t.Error("Expected an error for a missing file")
+		}
+
+		if err := r.ServeFile("/fallback.txt", fallback, ServeFileOptions{Validate: true}); err != nil {
+			t.Errorf("Expected no error for an existing file, got %v", err)
+		}
+	})
+
+	t.Run("Serve fallback for missing file", func(t *testing.T) {
+		mux := http.NewServeMux()
+		r := New(mux, "Example API", "1.0.0")
+
+		_ = r.ServeFile("/gone.txt", filepath.Join(dir, "gone.txt"), ServeFileOptions{Fallback: fallback})
+		_ = r.ServeFile("/strict.txt", filepath.Join(dir, "gone.txt"))
+
+		req := httptest.NewRequest(http.MethodGet, "/gone.txt", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK || w.Body.String() != "fallback" {
+			t.Errorf("Expected fallback content, got %d %q", w.Code, w.Body.String())
+		}
+
+		req = httptest.NewRequest(http.MethodGet, "/strict.txt", nil)
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != http.StatusNotFound {
+			t.Errorf("Expected status %d without fallback, got %d", http.StatusNotFound, w.Code)
+		}
+	})
+}
@@ -0,0 +1,101 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestJSONConfig(t *testing.T) {
+	type message struct {
+		Text string `json:"text"`
+	}
+
+	t.Run("Encoder follows the configuration", func(t *testing.T) {
+		mux := http.NewServeMux()
+		r := New(mux, "Example API", "1.0.0")
+		r.SetJSONConfig(JSONConfig{DisableHTMLEscape: true})
+
+		r.Get("/message", func(w http.ResponseWriter, req *http.Request) {
+			_ = JSON(w, req, http.StatusOK, message{Text: "Tom & Jerry"})
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/message", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if body := w.Body.String(); body != `{"text":"Tom & Jerry"}`+"\n" {
+			t.Errorf("Expected unescaped body, got %q", body)
+		}
+		if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+			t.Errorf("Expected Content-Type application/json, got %q", ct)
+		}
+	})
+
+	t.Run("Document follows the indentation", func(t *testing.T) {
+		for name, tt := range map[string]struct {
+			config *JSONConfig
+			prefix string
+		}{
+			"Default": {nil, "{\n  \"openapi\""},
+			"Compact": {&JSONConfig{}, `{"openapi"`},
+			"Tabs":    {&JSONConfig{Indent: "\t"}, "{\n\t\"openapi\""},
+		} {
+			mux := http.NewServeMux()
+			r := New(mux, "Example API", "1.0.0")
+			if tt.config != nil {
+				r.SetJSONConfig(*tt.config)
+			}
+			r.ServeOpenAPI("/openapi.json")
+
+			req := httptest.NewRequest(http.MethodGet, "/openapi.json", nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if body := w.Body.String(); !strings.HasPrefix(body, tt.prefix) {
+				t.Errorf("%s: Expected the document to start with %q, got %q", name, tt.prefix, body)
+			}
+		}
+	})
+
+	t.Run("Default escapes HTML", func(t *testing.T) {
+		mux := http.NewServeMux()
+		r := New(mux, "Example API", "1.0.0")
+
+		r.Get("/message", func(w http.ResponseWriter, req *http.Request) {
+			_ = JSON(w, req, http.StatusOK, message{Text: "Tom & Jerry"})
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/message", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if body := w.Body.String(); body != `{"text":"Tom \u0026 Jerry"}`+"\n" {
+			t.Errorf("Expected escaped body, got %q", body)
+		}
+	})
+
+	t.Run("Decoder follows the configuration", func(t *testing.T) {
+		mux := http.NewServeMux()
+		r := New(mux, "Example API", "1.0.0")
+		r.SetJSONConfig(JSONConfig{DisallowUnknownFields: true})
+
+		r.Post("/message", func(w http.ResponseWriter, req *http.Request) {
+			var m message
+			if err := Bind(req, &m); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		})
+
+		req := httptest.NewRequest(http.MethodPost, "/message", strings.NewReader(`{"text":"hi","extra":true}`))
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected unknown field to be rejected with %d, got %d", http.StatusBadRequest, w.Code)
+		}
+	})
+}
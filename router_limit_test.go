@@ -0,0 +1,61 @@
+package router
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMaxBodyBytes(t *testing.T) {
+	mux := http.NewServeMux()
+	r := New(mux, "Example API", "1.0.0")
+
+	handler := func(w http.ResponseWriter, req *http.Request) {
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			var maxErr *http.MaxBytesError
+			if errors.As(err, &maxErr) {
+				http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+				return
+			}
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write(body)
+	}
+
+	r.Post("/upload", handler, Docs{MaxBodyBytes: 16})
+	r.Post("/json", handler)
+
+	body := strings.Repeat("x", 32)
+
+	testCases := []struct {
+		name    string
+		path    string
+		chunked bool
+		status  int
+	}{
+		{"Limited route rejects oversized body", "/upload", false, http.StatusRequestEntityTooLarge},
+		{"Limited route rejects oversized chunked body", "/upload", true, http.StatusRequestEntityTooLarge},
+		{"Default route allows the body", "/json", false, http.StatusOK},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tc.path, strings.NewReader(body))
+			if tc.chunked {
+				req.ContentLength = -1
+			}
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tc.status {
+				t.Errorf("Expected status %d, got %d", tc.status, w.Code)
+			}
+		})
+	}
+}
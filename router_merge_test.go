@@ -0,0 +1,175 @@
+package router
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMerge(t *testing.T) {
+	newPlugin := func(header string, object any) *Router {
+		p := New(http.NewServeMux(), "Plugin", "1.0.0")
+		p.UseOpenapiDocs(true)
+		p.Use(func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("X-Plugin", header)
+				next.ServeHTTP(w, r)
+			})
+		})
+
+		p.Get("/users/{id}", func(w http.ResponseWriter, r *http.Request) {
+			_, _ = fmt.Fprintf(w, "%s user %s at %s", header, r.PathValue("id"), r.URL.Path)
+		}, Docs{
+			Summary: "Get User",
+			Out: map[string]DocOut{
+				"200": {
+					ApplicationType: "application/json",
+					Description:     "The user object.",
+					Object:          object,
+				},
+			},
+		})
+
+		return p
+	}
+
+	type User struct {
+		Name string `json:"name"`
+	}
+
+	mux := http.NewServeMux()
+	r := New(mux, "Example API", "1.0.0")
+	r.UseOpenapiDocs(true)
+
+	accounts := newPlugin("accounts", User{})
+	billing := newPlugin("billing", User{})
+	// force a name collision with a different User schema
+	billing.OpenAPI().Components.Schemas["User"] = Schema{
+		Type:       "object",
+		Properties: map[string]Schema{"id": {Type: "string"}},
+	}
+
+	r.Merge("/accounts", accounts)
+	r.Merge("/billing", billing)
+
+	for _, tc := range []struct {
+		path, body, header string
+	}{
+		{"/accounts/users/1", "accounts user 1 at /users/1", "accounts"},
+		{"/billing/users/2", "billing user 2 at /users/2", "billing"},
+	} {
+		req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Body.String() != tc.body {
+			t.Errorf("For path %s, expected %q, got %q", tc.path, tc.body, w.Body.String())
+		}
+		if w.Header().Get("X-Plugin") != tc.header {
+			t.Errorf("For path %s, expected X-Plugin %q, got %q", tc.path, tc.header, w.Header().Get("X-Plugin"))
+		}
+	}
+
+	doc := r.OpenAPI()
+	for _, path := range []string{"/accounts/users/{id}", "/billing/users/{id}"} {
+		if doc.Paths[path].Get == nil {
+			t.Errorf("Expected merged operation for %s", path)
+		}
+	}
+
+	if _, ok := doc.Components.Schemas["User"]; !ok {
+		t.Error("Expected User schema")
+	}
+	if _, ok := doc.Components.Schemas["User2"]; !ok {
+		t.Error("Expected colliding schema to be renamed to User2")
+	}
+
+	ref := doc.Paths["/billing/users/{id}"].Get.Responses["200"].Content["application/json"].Schema.Ref
+	if ref != "#/components/schemas/User2" {
+		t.Errorf("Expected billing response to reference User2, got %q", ref)
+	}
+
+	if err := doc.Validate(); err != nil {
+		t.Errorf("Expected merged document to be valid, got %v", err)
+	}
+}
+
+func TestMergeCollisions(t *testing.T) {
+	mux := http.NewServeMux()
+	r := New(mux, "Example API", "1.0.0")
+	r.UseOpenapiDocs(true)
+	r.Get("/users", func(w http.ResponseWriter, r *http.Request) {}, Docs{Summary: "List users", OperationID: "listUsers"})
+	r.OpenAPI().Components.Schemas["User"] = Schema{Type: "object", Properties: map[string]Schema{"name": {Type: "string"}}}
+
+	plugin := New(http.NewServeMux(), "Plugin", "1.0.0")
+	plugin.UseOpenapiDocs(true)
+	plugin.Get("/users", func(w http.ResponseWriter, r *http.Request) {}, Docs{Summary: "List users", OperationID: "listUsers"})
+	plugin.Post("/users", func(w http.ResponseWriter, r *http.Request) {}).
+		Summary("Create user").
+		Accepted("listUsers", nil)
+	plugin.OpenAPI().Components.Schemas["User"] = Schema{Type: "object", Properties: map[string]Schema{"id": {Type: "string"}}}
+	plugin.OpenAPI().Components.Schemas["User2"] = Schema{Type: "object", Properties: map[string]Schema{"email": {Type: "string"}}}
+
+	r.Merge("/plugin", plugin)
+
+	doc := r.OpenAPI()
+	for name, property := range map[string]string{"User": "name", "User2": "email", "User3": "id"} {
+		if _, ok := doc.Components.Schemas[name].Properties[property]; !ok {
+			t.Errorf("Expected schema %s with property %s, got %+v", name, property, doc.Components.Schemas[name])
+		}
+	}
+
+	merged := doc.Paths["/plugin/users"]
+	if merged.Get.OperationID != "listUsers2" {
+		t.Errorf("Expected merged operationId %q, got %q", "listUsers2", merged.Get.OperationID)
+	}
+	if link := merged.Post.Responses["202"].Links["status"]; link.OperationID != "listUsers2" {
+		t.Errorf("Expected the link to follow the rename to %q, got %q", "listUsers2", link.OperationID)
+	}
+	if plugin.OpenAPI().Paths["/users"].Get.OperationID != "listUsers" {
+		t.Error("Expected the merged router to keep its operationIds")
+	}
+
+	if err := r.Check(); err != nil {
+		t.Errorf("Expected no problems, got %v", err)
+	}
+}
+
+func TestMergeHost(t *testing.T) {
+	mux := http.NewServeMux()
+	r := New(mux, "Example API", "1.0.0")
+
+	plugin := New(http.NewServeMux(), "Plugin", "1.0.0")
+	plugin.Host("a.example.com").Get("/h", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("a"))
+	})
+	plugin.Get("/any", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("any"))
+	})
+
+	r.Merge("/plugin", plugin)
+
+	for _, tc := range []struct {
+		host, path string
+		status     int
+	}{
+		{"a.example.com", "/plugin/h", http.StatusOK},
+		{"b.example.com", "/plugin/h", http.StatusNotFound},
+		{"b.example.com", "/plugin/any", http.StatusOK},
+	} {
+		req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+		req.Host = tc.host
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != tc.status {
+			t.Errorf("%s%s: Expected status %d, got %d", tc.host, tc.path, tc.status, w.Code)
+		}
+	}
+
+	routes := r.Routes()
+	if routes[0].Host != "a.example.com" || routes[0].Pattern != "/plugin/h" {
+		t.Errorf("Expected the merged route to keep its host, got %+v", routes[0])
+	}
+}
@@ -1,9 +1,22 @@
 package router
 
 import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/donseba/go-router/middleware"
 )
@@ -32,6 +45,46 @@ func TestRecover(t *testing.T) {
 		}
 
 	})
+
+	t.Run("Log and report the request", func(t *testing.T) {
+		mux := http.NewServeMux()
+		r := New(mux, "Example API", "1.0.0")
+
+		var (
+			buf      bytes.Buffer
+			reported []middleware.PanicEntry
+		)
+		r.Use(middleware.RequestID)
+		r.Use(middleware.RecoverWithOptions(middleware.RecoverOptions{
+			Output: &buf,
+			Report: func(entry middleware.PanicEntry) {
+				reported = append(reported, entry)
+			},
+		}))
+
+		r.Get("/orders/{id}", func(w http.ResponseWriter, r *http.Request) {
+			panic("boom")
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/orders/42", nil)
+		req.Header.Set("X-Request-ID", "req-9")
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusInternalServerError {
+			t.Errorf("Expected status code %d, got %d", http.StatusInternalServerError, rr.Code)
+		}
+
+		want := "[go-router] panic serving GET /orders/42: boom (request_id=req-9)\n"
+		if buf.String() != want {
+			t.Errorf("Expected log line %q, got %q", want, buf.String())
+		}
+
+		expected := middleware.PanicEntry{Value: "boom", Method: http.MethodGet, Path: "/orders/42", RequestID: "req-9"}
+		if len(reported) != 1 || reported[0] != expected {
+			t.Errorf("Expected report %+v, got %+v", expected, reported)
+		}
+	})
 }
 
 func TestTimer(t *testing.T) {
@@ -58,3 +111,1324 @@ func TestTimer(t *testing.T) {
 		}
 	})
 }
+
+func TestTimerWithOptions(t *testing.T) {
+	t.Run("Custom writer and format", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		mux := http.NewServeMux()
+		r := New(mux, "Example API", "1.0.0")
+		r.Use(middleware.TimerWithOptions(middleware.TimerOptions{
+			Output:        &buf,
+			Format:        "{{.Method}} {{.Path}} {{.Status}} {{.Bytes}}",
+			IncludeStatus: true,
+		}))
+
+		r.Get("/timer", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte("hello"))
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/timer", nil)
+		r.ServeHTTP(httptest.NewRecorder(), req)
+
+		if got := buf.String(); got != "GET /timer 201 5\n" {
+			t.Errorf("Unexpected log output %q", got)
+		}
+	})
+
+	t.Run("Structured logger", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		mux := http.NewServeMux()
+		r := New(mux, "Example API", "1.0.0")
+		r.Use(middleware.TimerWithOptions(middleware.TimerOptions{
+			Logger:        slog.New(slog.NewTextHandler(&buf, nil)),
+			IncludeStatus: true,
+		}))
+
+		r.Get("/timer", func(w http.ResponseWriter, r *http.Request) {})
+
+		req := httptest.NewRequest(http.MethodGet, "/timer", nil)
+		r.ServeHTTP(httptest.NewRecorder(), req)
+
+		for _, attr := range []string{"msg=request", "method=GET", "path=/timer", "status=200", "bytes=0"} {
+			if !strings.Contains(buf.String(), attr) {
+				t.Errorf("Expected %q in log output %q", attr, buf.String())
+			}
+		}
+	})
+}
+
+func TestStatusHandlerMiddleware(t *testing.T) {
+	t.Run("Access log records custom 404", func(t *testing.T) {
+		mux := http.NewServeMux()
+		r := New(mux, "Example API", "1.0.0")
+
+		var logged []int
+		r.Use(func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				sw := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+				next.ServeHTTP(sw, req)
+				logged = append(logged, sw.status)
+			})
+		})
+
+		r.HandleStatus(http.StatusNotFound, func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "custom not found", http.StatusNotFound)
+		})
+
+		r.Get("/exists", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/missing", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != http.StatusNotFound || w.Body.String() != "custom not found\n" {
+			t.Errorf("Expected custom 404 response, got %d %q", w.Code, w.Body.String())
+		}
+
+		if len(logged) != 1 || logged[0] != http.StatusNotFound {
+			t.Errorf("Expected access log to record a single 404, got %v", logged)
+		}
+	})
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (s *statusRecorder) WriteHeader(status int) {
+	s.status = status
+	s.ResponseWriter.WriteHeader(status)
+}
+
+func TestCleanHopHeaders(t *testing.T) {
+	t.Run("Strip hop-by-hop headers", func(t *testing.T) {
+		mux := http.NewServeMux()
+		r := New(mux, "Example API", "1.0.0")
+
+		r.Use(middleware.CleanHopHeaders())
+
+		var received http.Header
+		r.Get("/proxy", func(w http.ResponseWriter, r *http.Request) {
+			received = r.Header.Clone()
+			w.WriteHeader(http.StatusOK)
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/proxy", nil)
+		req.Header.Set("Connection", "keep-alive, X-Hop")
+		req.Header.Set("Keep-Alive", "timeout=5")
+		req.Header.Set("Te", "trailers")
+		req.Header.Set("Upgrade", "websocket")
+		req.Header.Set("X-Hop", "remove me")
+		req.Header.Set("X-End-To-End", "keep me")
+		req.Header.Set("Authorization", "Bearer token")
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		for _, h := range []string{"Connection", "Keep-Alive", "Te", "Upgrade", "X-Hop"} {
+			if v := received.Get(h); v != "" {
+				t.Errorf("Expected header %s to be stripped, got %q", h, v)
+			}
+		}
+
+		if received.Get("X-End-To-End") != "keep me" {
+			t.Errorf("Expected X-End-To-End to pass through, got %q", received.Get("X-End-To-End"))
+		}
+		if received.Get("Authorization") != "Bearer token" {
+			t.Errorf("Expected Authorization to pass through, got %q", received.Get("Authorization"))
+		}
+	})
+}
+
+func TestCompressContentLength(t *testing.T) {
+	body := strings.Repeat("compress me please ", 100)
+
+	testCases := []struct {
+		name                string
+		middlewares         []Middleware
+		expectContentLength bool
+	}{
+		{
+			name:                "ContentLength wraps Compress",
+			middlewares:         []Middleware{middleware.ContentLengthMiddleware, middleware.Compress},
+			expectContentLength: true,
+		},
+		{
+			name:                "Compress wraps ContentLength",
+			middlewares:         []Middleware{middleware.Compress, middleware.ContentLengthMiddleware},
+			expectContentLength: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			r := New(mux, "Example API", "1.0.0")
+
+			for _, m := range tc.middlewares {
+				r.Use(m)
+			}
+
+			r.Get("/text", func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+				w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+				_, _ = io.WriteString(w, body)
+			})
+
+			ts := httptest.NewServer(r)
+			defer ts.Close()
+
+			req, _ := http.NewRequest(http.MethodGet, ts.URL+"/text", nil)
+			req.Header.Set("Accept-Encoding", "gzip")
+
+			res, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer res.Body.Close()
+
+			raw, err := io.ReadAll(res.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if res.Header.Get("Content-Encoding") != "gzip" {
+				t.Fatalf("Expected gzip Content-Encoding, got %q", res.Header.Get("Content-Encoding"))
+			}
+
+			// the server may still compute the length of small unflushed
+			// responses, but it must never describe the uncompressed body
+			cl := res.Header.Get("Content-Length")
+			if tc.expectContentLength && cl == "" {
+				t.Errorf("Expected Content-Length to be set")
+			}
+			if cl != "" && cl != strconv.Itoa(len(raw)) {
+				t.Errorf("Expected Content-Length %d, got %q", len(raw), cl)
+			}
+
+			gz, err := gzip.NewReader(bytes.NewReader(raw))
+			if err != nil {
+				t.Fatal(err)
+			}
+			decoded, err := io.ReadAll(gz)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if string(decoded) != body {
+				t.Errorf("Expected decoded body to match the original")
+			}
+		})
+	}
+}
+
+func TestSlidingWindow(t *testing.T) {
+	t.Run("Exhaust and reset window", func(t *testing.T) {
+		mux := http.NewServeMux()
+		r := New(mux, "Example API", "1.0.0")
+
+		window := 100 * time.Millisecond
+		r.Use(middleware.SlidingWindow(3, window, nil))
+
+		r.Get("/limited", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		})
+
+		do := func() *httptest.ResponseRecorder {
+			req := httptest.NewRequest(http.MethodGet, "/limited", nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			return w
+		}
+
+		for i := 0; i < 3; i++ {
+			w := do()
+			if w.Code != http.StatusOK {
+				t.Fatalf("Request %d: Expected status %d, got %d", i+1, http.StatusOK, w.Code)
+			}
+			if remaining := w.Header().Get("X-RateLimit-Remaining"); remaining != strconv.Itoa(2-i) {
+				t.Errorf("Request %d: Expected X-RateLimit-Remaining %d, got %q", i+1, 2-i, remaining)
+			}
+		}
+
+		w := do()
+		if w.Code != http.StatusTooManyRequests {
+			t.Fatalf("Expected status %d, got %d", http.StatusTooManyRequests, w.Code)
+		}
+		if w.Header().Get("X-RateLimit-Remaining") != "0" || w.Header().Get("X-RateLimit-Reset") == "" {
+			t.Errorf("Expected rate limit headers on rejection, got %v", w.Header())
+		}
+
+		time.Sleep(window + 20*time.Millisecond)
+
+		if w := do(); w.Code != http.StatusOK {
+			t.Errorf("Expected status %d after the window elapsed, got %d", http.StatusOK, w.Code)
+		}
+	})
+
+	t.Run("Keys are limited independently", func(t *testing.T) {
+		handler := middleware.SlidingWindow(1, time.Minute, func(r *http.Request) string {
+			return r.Header.Get("X-Client")
+		})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+		var wg sync.WaitGroup
+		codes := make(chan int, 10)
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				req := httptest.NewRequest(http.MethodGet, "/", nil)
+				req.Header.Set("X-Client", "a")
+				w := httptest.NewRecorder()
+				handler.ServeHTTP(w, req)
+				codes <- w.Code
+			}()
+		}
+		wg.Wait()
+		close(codes)
+
+		var allowed int
+		for code := range codes {
+			if code == http.StatusOK {
+				allowed++
+			}
+		}
+		if allowed != 1 {
+			t.Errorf("Expected exactly one allowed request for client a, got %d", allowed)
+		}
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("X-Client", "b")
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Errorf("Expected client b to be allowed, got %d", w.Code)
+		}
+	})
+}
+
+func TestPropagateTrace(t *testing.T) {
+	serve := func(format middleware.TraceFormat, headers map[string]string) (middleware.TraceContext, *http.Request) {
+		mux := http.NewServeMux()
+		r := New(mux, "Example API", "1.0.0")
+		r.Use(middleware.PropagateTrace(format))
+
+		var (
+			tc       middleware.TraceContext
+			outgoing *http.Request
+		)
+		r.Get("/trace", func(w http.ResponseWriter, req *http.Request) {
+			var ok bool
+			if tc, ok = middleware.TraceFromContext(req.Context()); !ok {
+				t.Error("Expected a trace context")
+			}
+			outgoing, _ = http.NewRequest(http.MethodGet, "http://backend/", nil)
+			middleware.InjectTrace(req.Context(), outgoing)
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/trace", nil)
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		r.ServeHTTP(httptest.NewRecorder(), req)
+
+		return tc, outgoing
+	}
+
+	t.Run("W3C traceparent", func(t *testing.T) {
+		tc, out := serve(middleware.TraceW3C, map[string]string{
+			"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		})
+
+		if tc.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" || tc.ParentSpanID != "00f067aa0ba902b7" || !tc.Sampled {
+			t.Errorf("Unexpected trace context %+v", tc)
+		}
+		if len(tc.SpanID) != 16 || tc.SpanID == tc.ParentSpanID {
+			t.Errorf("Expected a new span id, got %q", tc.SpanID)
+		}
+
+		expected := "00-4bf92f3577b34da6a3ce929d0e0e4736-" + tc.SpanID + "-01"
+		if out.Header.Get("traceparent") != expected {
+			t.Errorf("Expected outgoing traceparent %q, got %q", expected, out.Header.Get("traceparent"))
+		}
+	})
+
+	t.Run("B3 multi headers", func(t *testing.T) {
+		tc, out := serve(middleware.TraceB3, map[string]string{
+			"X-B3-TraceId": "463ac35c9f6413ad48485a3953bb6124",
+			"X-B3-SpanId":  "a2fb4a1d1a96d312",
+			"X-B3-Sampled": "0",
+		})
+
+		if tc.TraceID != "463ac35c9f6413ad48485a3953bb6124" || tc.ParentSpanID != "a2fb4a1d1a96d312" || tc.Sampled {
+			t.Errorf("Unexpected trace context %+v", tc)
+		}
+		if out.Header.Get("X-B3-TraceId") != tc.TraceID || out.Header.Get("X-B3-SpanId") != tc.SpanID || out.Header.Get("X-B3-Sampled") != "0" {
+			t.Errorf("Unexpected outgoing B3 headers %v", out.Header)
+		}
+	})
+
+	t.Run("B3 single header", func(t *testing.T) {
+		tc, _ := serve(middleware.TraceB3, map[string]string{
+			"b3": "80f198ee56343ba864fe8b2a57d3eff7-e457b5a2e4d86bd1-1",
+		})
+
+		if tc.TraceID != "80f198ee56343ba864fe8b2a57d3eff7" || tc.ParentSpanID != "e457b5a2e4d86bd1" || !tc.Sampled {
+			t.Errorf("Unexpected trace context %+v", tc)
+		}
+	})
+
+	t.Run("Invalid header starts a new trace", func(t *testing.T) {
+		tc, _ := serve(middleware.TraceW3C, map[string]string{
+			"traceparent": "00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		})
+
+		if len(tc.TraceID) != 32 || tc.TraceID == "00000000000000000000000000000000" || tc.ParentSpanID != "" {
+			t.Errorf("Expected a new trace, got %+v", tc)
+		}
+	})
+}
+
+func TestLimitHeaders(t *testing.T) {
+	mux := http.NewServeMux()
+	r := New(mux, "Example API", "1.0.0")
+
+	r.Use(middleware.LimitHeaders(5, 256))
+
+	r.Get("/headers", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	testCases := []struct {
+		name    string
+		headers map[string]string
+		status  int
+	}{
+		{"Within limits", map[string]string{"X-One": "1", "X-Two": "2"}, http.StatusOK},
+		{"Too many headers", map[string]string{"X-1": "1", "X-2": "2", "X-3": "3", "X-4": "4", "X-5": "5", "X-6": "6"}, http.StatusRequestHeaderFieldsTooLarge},
+		{"Headers too large", map[string]string{"X-Large": strings.Repeat("a", 300)}, http.StatusRequestHeaderFieldsTooLarge},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/headers", nil)
+			for k, v := range tc.headers {
+				req.Header.Set(k, v)
+			}
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tc.status {
+				t.Errorf("Expected status %d, got %d", tc.status, w.Code)
+			}
+		})
+	}
+}
+
+func TestContentLengthHead(t *testing.T) {
+	mux := http.NewServeMux()
+	r := New(mux, "Example API", "1.0.0")
+
+	r.Use(middleware.ContentLengthMiddleware)
+
+	r.Get("/resource", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "Hello, World!")
+	})
+
+	for _, method := range []string{http.MethodGet, http.MethodHead} {
+		t.Run(method, func(t *testing.T) {
+			req := httptest.NewRequest(method, "/resource", nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Errorf("Expected status %d, got %d", http.StatusOK, w.Code)
+			}
+			if cl := w.Header().Get("Content-Length"); cl != "13" {
+				t.Errorf("Expected Content-Length 13, got %q", cl)
+			}
+
+			expected := "Hello, World!"
+			if method == http.MethodHead {
+				expected = ""
+			}
+			if w.Body.String() != expected {
+				t.Errorf("Expected body %q, got %q", expected, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestCORSAllowedMethods(t *testing.T) {
+	mux := http.NewServeMux()
+	r := New(mux, "Example API", "1.0.0")
+
+	r.Get("/users", func(w http.ResponseWriter, r *http.Request) {})
+	r.Post("/users", func(w http.ResponseWriter, r *http.Request) {})
+	r.Delete("/users/{id}", func(w http.ResponseWriter, r *http.Request) {})
+
+	handler := middleware.CORS(middleware.CORSOptions{
+		AllowedOrigins:     []string{"*"},
+		AllowedMethods:     []string{http.MethodGet, http.MethodPut},
+		AllowedMethodsFunc: r.AllowedMethods,
+	})(r)
+
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{"/users", "GET, HEAD, POST"},
+		{"/users/42", "DELETE"},
+		{"/unknown", "GET, PUT"},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodOptions, tt.path, nil)
+		req.Header.Set("Origin", "https://example.com")
+		req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+		rr := httptest.NewRecorder()
+
+		handler.ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusNoContent {
+			t.Errorf("%s: Expected status %d, got %d", tt.path, http.StatusNoContent, rr.Code)
+		}
+		if got := rr.Header().Get("Access-Control-Allow-Methods"); got != tt.expected {
+			t.Errorf("%s: Expected Access-Control-Allow-Methods %q, got %q", tt.path, tt.expected, got)
+		}
+	}
+}
+
+func TestRequireNonce(t *testing.T) {
+	mux := http.NewServeMux()
+	r := New(mux, "Example API", "1.0.0")
+	r.Use(middleware.RequireNonce(middleware.NewMemoryNonceStore(time.Minute), "X-Nonce"))
+
+	r.Post("/transfer", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	send := func(nonce string) int {
+		req := httptest.NewRequest(http.MethodPost, "/transfer", nil)
+		if nonce != "" {
+			req.Header.Set("X-Nonce", nonce)
+		}
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+		return rr.Code
+	}
+
+	if code := send("abc"); code != http.StatusOK {
+		t.Errorf("Expected fresh nonce to pass with %d, got %d", http.StatusOK, code)
+	}
+	if code := send("abc"); code != http.StatusConflict {
+		t.Errorf("Expected replayed nonce to be rejected with %d, got %d", http.StatusConflict, code)
+	}
+	if code := send("def"); code != http.StatusOK {
+		t.Errorf("Expected another fresh nonce to pass with %d, got %d", http.StatusOK, code)
+	}
+	if code := send(""); code != http.StatusBadRequest {
+		t.Errorf("Expected missing nonce to be rejected with %d, got %d", http.StatusBadRequest, code)
+	}
+}
+
+func TestMaxPathDepth(t *testing.T) {
+	mux := http.NewServeMux()
+	r := New(mux, "Example API", "1.0.0")
+	r.Use(middleware.MaxPathDepth(3))
+
+	r.Get("/files/{path...}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	tests := []struct {
+		path     string
+		expected int
+	}{
+		{"/files/a/b", http.StatusOK},
+		{"/files/a/b/c", http.StatusBadRequest},
+		{"/files/a/b/c/d/e/f", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+
+		if rr.Code != tt.expected {
+			t.Errorf("%s: Expected status %d, got %d", tt.path, tt.expected, rr.Code)
+		}
+	}
+}
+
+func TestMiddlewareFor(t *testing.T) {
+	mux := http.NewServeMux()
+	r := New(mux, "Example API", "1.0.0")
+	r.Use(middleware.Timer)
+	r.Use(middleware.CleanHopHeaders())
+
+	r.Group("/api", func(api *Router) {
+		api.Use(middleware.MaxPathDepth(5))
+		api.Get("/users", func(w http.ResponseWriter, r *http.Request) {})
+	})
+
+	expected := []string{"middleware.Timer", "middleware.CleanHopHeaders", "middleware.MaxPathDepth"}
+	if chain := r.MiddlewareFor(http.MethodGet, "/api/users"); !slices.Equal(chain, expected) {
+		t.Errorf("Expected chain %v, got %v", expected, chain)
+	}
+
+	if chain := r.MiddlewareFor(http.MethodPost, "/api/users"); chain != nil {
+		t.Errorf("Expected no chain for an unknown route, got %v", chain)
+	}
+}
+
+func TestRouteRecoverHandler(t *testing.T) {
+	mux := http.NewServeMux()
+	r := New(mux, "Example API", "1.0.0")
+	r.Use(middleware.Recover)
+
+	r.Get("/risky", func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}, Docs{
+		RecoverHandler: func(w http.ResponseWriter, req *http.Request, recovered any) {
+			w.WriteHeader(http.StatusPartialContent)
+			fmt.Fprintf(w, "partial: %v", recovered)
+		},
+	})
+	r.Get("/other", func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/risky", nil)
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusPartialContent || rr.Body.String() != "partial: boom" {
+		t.Errorf("Expected the route's recover handler response, got %d %q", rr.Code, rr.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/other", nil)
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusInternalServerError {
+		t.Errorf("Expected the global recover for other routes, got %d", rr.Code)
+	}
+}
+
+func TestResponseStats(t *testing.T) {
+	var recorded ResponseStats
+
+	mux := http.NewServeMux()
+	r := New(mux, "Example API", "1.0.0")
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			next.ServeHTTP(w, req)
+			recorded = *ResponseStatsFrom(req.Context())
+		})
+	})
+
+	r.Post("/users", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte("created"))
+		_, _ = w.Write([]byte(" user"))
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/users", nil)
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	if recorded.Status != http.StatusCreated || recorded.Bytes != len("created user") {
+		t.Errorf("Expected status %d and %d bytes, got %+v", http.StatusCreated, len("created user"), recorded)
+	}
+}
+
+func TestStripResponseHeaders(t *testing.T) {
+	mux := http.NewServeMux()
+	r := New(mux, "Example API", "1.0.0")
+	r.Use(middleware.StripResponseHeaders("Server", "X-Powered-By"))
+
+	r.Get("/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Server", "backend/1.2.3")
+		w.Header().Set("X-Powered-By", "PHP/8")
+		w.Header().Set("X-Request-Id", "42")
+		_, _ = w.Write([]byte("ok"))
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	for _, name := range []string{"Server", "X-Powered-By"} {
+		if v := rr.Header().Get(name); v != "" {
+			t.Errorf("Expected %s to be stripped, got %q", name, v)
+		}
+	}
+	if rr.Header().Get("X-Request-Id") != "42" {
+		t.Error("Expected X-Request-Id to be kept")
+	}
+	if rr.Body.String() != "ok" {
+		t.Errorf("Unexpected body %q", rr.Body.String())
+	}
+}
+
+func TestStripResponseHeadersWithoutBody(t *testing.T) {
+	mux := http.NewServeMux()
+	r := New(mux, "Example API", "1.0.0")
+	r.Use(middleware.StripResponseHeaders("Server"))
+
+	// the handler returns without writing, net/http sends the implicit 200
+	r.Get("/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Server", "leak")
+	})
+
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	res, err := http.Get(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+
+	if v := res.Header.Get("Server"); v != "" {
+		t.Errorf("Expected Server to be stripped, got %q", v)
+	}
+}
+
+func TestCanonicalHost(t *testing.T) {
+	mux := http.NewServeMux()
+	r := New(mux, "Example API", "1.0.0")
+	r.Use(middleware.CanonicalHost("example.com", http.StatusPermanentRedirect, "/healthz"))
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}
+	r.Get("/users", handler)
+	r.Get("/healthz", handler)
+
+	tests := []struct {
+		url      string
+		expected int
+		location string
+	}{
+		{"http://www.example.com/users?page=2", http.StatusPermanentRedirect, "http://example.com/users?page=2"},
+		{"https://www.example.com/users", http.StatusPermanentRedirect, "https://example.com/users"},
+		{"http://example.com/users", http.StatusOK, ""},
+		{"http://EXAMPLE.com/users", http.StatusOK, ""},
+		{"http://10.0.0.1/healthz", http.StatusOK, ""},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+
+		if rr.Code != tt.expected {
+			t.Errorf("%s: Expected status %d, got %d", tt.url, tt.expected, rr.Code)
+		}
+		if loc := rr.Header().Get("Location"); loc != tt.location {
+			t.Errorf("%s: Expected location %q, got %q", tt.url, tt.location, loc)
+		}
+	}
+}
+
+func TestSecureCookies(t *testing.T) {
+	mux := http.NewServeMux()
+	r := New(mux, "Example API", "1.0.0")
+	r.Use(middleware.SecureCookies(middleware.CookieDefaults{
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+		Secure:   true,
+	}))
+
+	r.Get("/login", func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "abc", Path: "/"})
+		http.SetCookie(w, &http.Cookie{Name: "theme", Value: "dark", SameSite: http.SameSiteStrictMode})
+		_, _ = w.Write([]byte("ok"))
+	})
+
+	for _, tt := range []struct {
+		url    string
+		secure bool
+	}{
+		{"http://example.com/login", false},
+		{"https://example.com/login", true},
+	} {
+		req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+
+		cookies := rr.Result().Cookies()
+		if len(cookies) != 2 {
+			t.Fatalf("%s: Expected 2 cookies, got %d", tt.url, len(cookies))
+		}
+		for _, c := range cookies {
+			if !c.HttpOnly {
+				t.Errorf("%s: Expected cookie %s to be HttpOnly", tt.url, c.Name)
+			}
+			if c.Secure != tt.secure {
+				t.Errorf("%s: Expected cookie %s secure %v, got %v", tt.url, c.Name, tt.secure, c.Secure)
+			}
+		}
+		if cookies[0].SameSite != http.SameSiteLaxMode {
+			t.Errorf("%s: Expected session cookie SameSite=Lax, got %v", tt.url, cookies[0].SameSite)
+		}
+		if cookies[1].SameSite != http.SameSiteStrictMode {
+			t.Errorf("%s: Expected theme cookie to keep SameSite=Strict, got %v", tt.url, cookies[1].SameSite)
+		}
+		if cookies[0].Path != "/" {
+			t.Errorf("%s: Expected the cookie path to be kept, got %q", tt.url, cookies[0].Path)
+		}
+	}
+}
+
+func TestSecureCookiesWithoutBody(t *testing.T) {
+	mux := http.NewServeMux()
+	r := New(mux, "Example API", "1.0.0")
+	r.Use(middleware.SecureCookies(middleware.CookieDefaults{
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+		Secure:   true,
+	}))
+
+	// the handler returns without writing, net/http sends the implicit 200
+	r.Get("/logout", func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "", Path: "/", MaxAge: -1})
+	})
+
+	ts := httptest.NewTLSServer(r)
+	defer ts.Close()
+
+	res, err := ts.Client().Get(ts.URL + "/logout")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+
+	cookies := res.Cookies()
+	if len(cookies) != 1 {
+		t.Fatalf("Expected 1 cookie, got %d", len(cookies))
+	}
+	if c := cookies[0]; !c.HttpOnly || !c.Secure || c.SameSite != http.SameSiteLaxMode {
+		t.Errorf("Expected the cookie to be secured, got %q", res.Header.Get("Set-Cookie"))
+	}
+}
+
+func TestUsePolicy(t *testing.T) {
+	type roleKey struct{}
+
+	mux := http.NewServeMux()
+	r := New(mux, "Example API", "1.0.0")
+	r.UseContext(func(ctx context.Context, req *http.Request) context.Context {
+		return context.WithValue(ctx, roleKey{}, req.Header.Get("X-Role"))
+	})
+
+	var middlewareCalls int
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			middlewareCalls++
+			next.ServeHTTP(w, req)
+		})
+	})
+
+	r.UsePolicy(func(req *http.Request, docs Docs) int {
+		if slices.Contains(docs.Tags, "admin") && req.Context().Value(roleKey{}) != "admin" {
+			return http.StatusForbidden
+		}
+		return 0
+	})
+
+	handler := func(w http.ResponseWriter, req *http.Request) {
+		docs, _ := RouteDocsFrom(req.Context())
+		_, _ = w.Write([]byte(docs.Summary))
+	}
+	r.Get("/admin/users", handler).Summary("List all users").Tag("admin")
+	r.Get("/users/me", handler, Docs{Summary: "Current user"})
+
+	tests := []struct {
+		path     string
+		role     string
+		expected int
+		body     string
+	}{
+		{"/admin/users", "", http.StatusForbidden, ""},
+		{"/admin/users", "admin", http.StatusOK, "List all users"},
+		{"/users/me", "", http.StatusOK, "Current user"},
+	}
+
+	for _, tt := range tests {
+		middlewareCalls = 0
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		req.Header.Set("X-Role", tt.role)
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+
+		if rr.Code != tt.expected {
+			t.Errorf("%s as %q: Expected status %d, got %d", tt.path, tt.role, tt.expected, rr.Code)
+		}
+		if tt.body != "" && rr.Body.String() != tt.body {
+			t.Errorf("%s as %q: Expected body %q, got %q", tt.path, tt.role, tt.body, rr.Body.String())
+		}
+		if blocked := tt.expected == http.StatusForbidden; blocked != (middlewareCalls == 0) {
+			t.Errorf("%s as %q: Expected the policy to run before the middlewares", tt.path, tt.role)
+		}
+	}
+}
+
+func TestSequenceGuard(t *testing.T) {
+	mux := http.NewServeMux()
+	r := New(mux, "Example API", "1.0.0")
+	r.Use(middleware.SequenceGuard(middleware.NewMemorySequenceStore(time.Minute), "X-Sequence"))
+
+	r.Post("/cart/items", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	send := func(session, seq string) int {
+		req := httptest.NewRequest(http.MethodPost, "/cart/items", nil)
+		req.Header.Set("Authorization", "Bearer "+session)
+		if seq != "" {
+			req.Header.Set("X-Sequence", seq)
+		}
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+		return rr.Code
+	}
+
+	tests := []struct {
+		session  string
+		seq      string
+		expected int
+	}{
+		{"alice", "1", http.StatusOK},
+		{"alice", "2", http.StatusOK},
+		{"alice", "5", http.StatusOK},
+		{"alice", "5", http.StatusConflict},
+		{"alice", "3", http.StatusConflict},
+		{"bob", "1", http.StatusOK},
+		{"alice", "6", http.StatusOK},
+		{"alice", "", http.StatusBadRequest},
+		{"alice", "next", http.StatusBadRequest},
+	}
+
+	for i, tt := range tests {
+		if code := send(tt.session, tt.seq); code != tt.expected {
+			t.Errorf("%d: %s sequence %q: Expected status %d, got %d", i, tt.session, tt.seq, tt.expected, code)
+		}
+	}
+}
+
+func TestMemorySequenceStoreExpiry(t *testing.T) {
+	ttl := 50 * time.Millisecond
+	store := middleware.NewMemorySequenceStore(ttl)
+
+	if !store.Advance("alice", 5) {
+		t.Fatal("Expected the first sequence number to be accepted")
+	}
+	if store.Advance("alice", 3) {
+		t.Error("Expected a lower sequence number to be rejected within the ttl")
+	}
+
+	time.Sleep(2 * ttl)
+
+	if !store.Advance("bob", 1) {
+		t.Error("Expected another session to be accepted")
+	}
+	if !store.Advance("alice", 3) {
+		t.Error("Expected the expired session to accept any sequence number")
+	}
+	if store.Advance("alice", 2) {
+		t.Error("Expected the renewed session to reject a lower sequence number")
+	}
+}
+
+func TestOnError(t *testing.T) {
+	mux := http.NewServeMux()
+	r := New(mux, "Example API", "1.0.0")
+
+	var statuses []int
+	r.Use(middleware.OnError(func(w http.ResponseWriter, r *http.Request, status int) {
+		statuses = append(statuses, status)
+		w.Header().Set("X-Support-ID", "support-42")
+	}))
+
+	r.Get("/ok", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	})
+	r.Get("/empty", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Empty", "true")
+	})
+	r.Get("/fail", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "boom", http.StatusInternalServerError)
+	})
+
+	tests := []struct {
+		path      string
+		status    int
+		supportID string
+	}{
+		{"/ok", http.StatusOK, ""},
+		{"/empty", http.StatusOK, ""},
+		{"/fail", http.StatusInternalServerError, "support-42"},
+	}
+
+	for _, tt := range tests {
+		statuses = nil
+
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+
+		if rr.Code != tt.status {
+			t.Errorf("%s: Expected status %d, got %d", tt.path, tt.status, rr.Code)
+		}
+		if got := rr.Header().Get("X-Support-ID"); got != tt.supportID {
+			t.Errorf("%s: Expected X-Support-ID %q, got %q", tt.path, tt.supportID, got)
+		}
+
+		if tt.supportID == "" && len(statuses) != 0 {
+			t.Errorf("%s: Expected no callback, got %v", tt.path, statuses)
+		}
+		if tt.supportID != "" && (len(statuses) != 1 || statuses[0] != tt.status) {
+			t.Errorf("%s: Expected one callback with %d, got %v", tt.path, tt.status, statuses)
+		}
+	}
+}
+
+func TestParseAuthorization(t *testing.T) {
+	mux := http.NewServeMux()
+	r := New(mux, "Example API", "1.0.0")
+	r.Use(middleware.ParseAuthorization())
+
+	r.Get("/me", func(w http.ResponseWriter, r *http.Request) {
+		scheme, token, ok := middleware.Authorization(r)
+		if !ok {
+			_, _ = w.Write([]byte("anonymous"))
+			return
+		}
+		_, _ = w.Write([]byte(scheme + " " + token))
+	})
+
+	tests := []struct {
+		name     string
+		header   string
+		status   int
+		expected string
+	}{
+		{"Bearer", "Bearer abc.def.ghi", http.StatusOK, "Bearer abc.def.ghi"},
+		{"Lowercase bearer", "bearer abc", http.StatusOK, "Bearer abc"},
+		{"Basic", "Basic dXNlcjpwYXNz", http.StatusOK, "Basic dXNlcjpwYXNz"},
+		{"Other scheme", "ApiKey k-123", http.StatusOK, "ApiKey k-123"},
+		{"Digest", `Digest username="u", realm="r", nonce="n"`, http.StatusOK, `Digest username="u", realm="r", nonce="n"`},
+		{"AWS SigV4", "AWS4-HMAC-SHA256 Credential=AKID/20260101/us-east-1/s3/aws4_request, Signature=abc", http.StatusOK, "AWS4-HMAC-SHA256 Credential=AKID/20260101/us-east-1/s3/aws4_request, Signature=abc"},
+		{"Missing", "", http.StatusOK, "anonymous"},
+		{"No credentials", "Bearer", http.StatusBadRequest, ""},
+		{"Spaces in token", "Bearer abc def", http.StatusBadRequest, ""},
+		{"Invalid base64", "Basic not-base64!", http.StatusBadRequest, ""},
+		{"Basic without colon", "Basic dXNlcg==", http.StatusBadRequest, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/me", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, req)
+
+			if rr.Code != tt.status {
+				t.Fatalf("Expected status %d, got %d", tt.status, rr.Code)
+			}
+			if tt.expected != "" && rr.Body.String() != tt.expected {
+				t.Errorf("Expected body %q, got %q", tt.expected, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestParseAuthorizationBasicAuth(t *testing.T) {
+	mux := http.NewServeMux()
+	r := New(mux, "Example API", "1.0.0")
+	r.Use(middleware.ParseAuthorization())
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// BasicAuth must read the parsed credentials, not the header
+			r.Header.Del("Authorization")
+			next.ServeHTTP(w, r)
+		})
+	})
+	r.Use(middleware.BasicAuth("Admin", middleware.BasicAuthUsers(map[string]string{"alice": "s3cret"})))
+
+	r.Get("/admin", func(w http.ResponseWriter, r *http.Request) {
+		user, _ := middleware.BasicAuthUser(r)
+		_, _ = w.Write([]byte("hello " + user))
+	})
+
+	tests := []struct {
+		name     string
+		header   string
+		status   int
+		expected string
+	}{
+		{"Basic", "Basic " + base64.StdEncoding.EncodeToString([]byte("alice:s3cret")), http.StatusOK, "hello alice"},
+		{"Wrong password", "Basic " + base64.StdEncoding.EncodeToString([]byte("alice:nope")), http.StatusUnauthorized, ""},
+		{"Bearer", "Bearer abc", http.StatusUnauthorized, ""},
+		{"Missing", "", http.StatusUnauthorized, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, req)
+
+			if rr.Code != tt.status {
+				t.Fatalf("Expected status %d, got %d", tt.status, rr.Code)
+			}
+			if tt.expected != "" && rr.Body.String() != tt.expected {
+				t.Errorf("Expected body %q, got %q", tt.expected, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestRequestID(t *testing.T) {
+	defer func(generate func() string) { middleware.GenerateRequestID = generate }(middleware.GenerateRequestID)
+	middleware.GenerateRequestID = func() string { return "generated-1" }
+
+	mux := http.NewServeMux()
+	r := New(mux, "Example API", "1.0.0")
+	r.Use(middleware.RequestID)
+
+	r.Get("/", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(middleware.RequestIDFromContext(r.Context())))
+	})
+
+	tests := []struct {
+		name     string
+		incoming string
+		expected string
+	}{
+		{"Incoming", "req-42", "req-42"},
+		{"Generated", "", "generated-1"},
+		{"Invalid", "bad id\n", "generated-1"},
+		{"Too long", strings.Repeat("a", 129), "generated-1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.incoming != "" {
+				req.Header.Set("X-Request-ID", tt.incoming)
+			}
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, req)
+
+			if got := rr.Header().Get("X-Request-ID"); got != tt.expected {
+				t.Errorf("Expected header %q, got %q", tt.expected, got)
+			}
+			if rr.Body.String() != tt.expected {
+				t.Errorf("Expected context ID %q, got %q", tt.expected, rr.Body.String())
+			}
+		})
+	}
+
+	if id := middleware.RequestIDFromContext(context.Background()); id != "" {
+		t.Errorf("Expected no ID without middleware, got %q", id)
+	}
+}
+
+func TestLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	mux := http.NewServeMux()
+	r := New(mux, "Example API", "1.0.0")
+	r.Use(middleware.RequestID)
+	r.Use(middleware.Logger(logger, middleware.LoggerOptions{Level: slog.LevelDebug}))
+
+	r.Post("/users", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte("created"))
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/users", nil)
+	req.Header.Set("X-Request-ID", "req-7")
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("Expected one record, got %d: %s", len(lines), buf.String())
+	}
+
+	var record map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &record); err != nil {
+		t.Fatal(err)
+	}
+
+	for key, want := range map[string]any{
+		"level":      "DEBUG",
+		"msg":        "request",
+		"method":     "POST",
+		"path":       "/users",
+		"status":     float64(http.StatusCreated),
+		"bytes":      float64(len("created")),
+		"request_id": "req-7",
+	} {
+		if record[key] != want {
+			t.Errorf("Expected %s %v, got %v", key, want, record[key])
+		}
+	}
+	if _, ok := record["duration"]; !ok {
+		t.Error("Expected a duration attribute")
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	t.Run("Burst then reject", func(t *testing.T) {
+		mux := http.NewServeMux()
+		r := New(mux, "Example API", "1.0.0")
+		r.Use(middleware.RateLimit(middleware.RateLimitOptions{Rate: 0.5, Burst: 3}))
+
+		r.Get("/limited", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		})
+
+		for i := 0; i < 4; i++ {
+			req := httptest.NewRequest(http.MethodGet, "/limited", nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if i < 3 {
+				if w.Code != http.StatusOK {
+					t.Fatalf("Request %d: Expected status %d, got %d", i+1, http.StatusOK, w.Code)
+				}
+				if remaining := w.Header().Get("X-RateLimit-Remaining"); remaining != strconv.Itoa(2-i) {
+					t.Errorf("Request %d: Expected X-RateLimit-Remaining %d, got %q", i+1, 2-i, remaining)
+				}
+				continue
+			}
+
+			if w.Code != http.StatusTooManyRequests {
+				t.Fatalf("Request %d: Expected status %d, got %d", i+1, http.StatusTooManyRequests, w.Code)
+			}
+			if w.Header().Get("X-RateLimit-Remaining") != "0" || w.Header().Get("Retry-After") != "2" {
+				t.Errorf("Expected rate limit headers on rejection, got %v", w.Header())
+			}
+		}
+	})
+
+	t.Run("Concurrent requests", func(t *testing.T) {
+		handler := middleware.RateLimit(middleware.RateLimitOptions{
+			Rate:  0.001,
+			Burst: 3,
+			Key: func(r *http.Request) string {
+				return r.Header.Get("X-Client")
+			},
+		})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+		var wg sync.WaitGroup
+		codes := make(chan int, 20)
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func(client string) {
+				defer wg.Done()
+				req := httptest.NewRequest(http.MethodGet, "/", nil)
+				req.Header.Set("X-Client", client)
+				w := httptest.NewRecorder()
+				handler.ServeHTTP(w, req)
+				codes <- w.Code
+			}([]string{"a", "b"}[i%2])
+		}
+		wg.Wait()
+		close(codes)
+
+		var allowed int
+		for code := range codes {
+			if code == http.StatusOK {
+				allowed++
+			}
+		}
+		if allowed != 6 {
+			t.Errorf("Expected 3 requests allowed per client, got %d in total", allowed)
+		}
+	})
+
+	for name, options := range map[string]middleware.RateLimitOptions{
+		"Non-positive rate": {Burst: 3},
+		"Empty burst":       {Rate: 10},
+	} {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Expected a panic for %+v", options)
+				}
+			}()
+			middleware.RateLimit(options)
+		})
+	}
+}
+
+func TestBasicAuth(t *testing.T) {
+	mux := http.NewServeMux()
+	r := New(mux, "Example API", "1.0.0")
+	r.Use(middleware.BasicAuth(`Admin "area"`, middleware.BasicAuthUsers(map[string]string{
+		"alice": "s3cret",
+		"bob":   "hunter2",
+	})))
+
+	r.Get("/admin", func(w http.ResponseWriter, r *http.Request) {
+		user, _ := middleware.BasicAuthUser(r)
+		_, _ = w.Write([]byte("hello " + user))
+	})
+
+	tests := []struct {
+		name     string
+		user     string
+		pass     string
+		auth     bool
+		status   int
+		expected string
+	}{
+		{"Missing", "", "", false, http.StatusUnauthorized, ""},
+		{"Wrong password", "alice", "hunter2", true, http.StatusUnauthorized, ""},
+		{"Unknown user", "mallory", "s3cret", true, http.StatusUnauthorized, ""},
+		{"Correct", "alice", "s3cret", true, http.StatusOK, "hello alice"},
+		{"Other user", "bob", "hunter2", true, http.StatusOK, "hello bob"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+			if tt.auth {
+				req.SetBasicAuth(tt.user, tt.pass)
+			}
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, req)
+
+			if rr.Code != tt.status {
+				t.Fatalf("Expected status %d, got %d", tt.status, rr.Code)
+			}
+
+			challenge := rr.Header().Get("WWW-Authenticate")
+			if tt.status == http.StatusUnauthorized && challenge != `Basic realm="Admin \"area\"", charset="UTF-8"` {
+				t.Errorf("Unexpected challenge %q", challenge)
+			}
+			if tt.status == http.StatusOK && (challenge != "" || rr.Body.String() != tt.expected) {
+				t.Errorf("Expected body %q without challenge, got %q and %q", tt.expected, rr.Body.String(), challenge)
+			}
+		})
+	}
+}
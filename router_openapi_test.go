@@ -0,0 +1,1176 @@
+package router
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOpenAPIValidate(t *testing.T) {
+	t.Run("Valid document", func(t *testing.T) {
+		mux := http.NewServeMux()
+		r := New(mux, "Example API", "1.0.0")
+		r.UseOpenapiDocs(true)
+
+		r.Get("/users", func(w http.ResponseWriter, r *http.Request) {}, Docs{
+			Summary: "User List",
+			Out: map[string]DocOut{
+				"200": {
+					ApplicationType: "application/json",
+					Description:     "The list of users.",
+				},
+			},
+		})
+
+		if err := r.OpenAPI().Validate(); err != nil {
+			t.Errorf("Expected no validation errors, got %v", err)
+		}
+	})
+
+	t.Run("Invalid operation", func(t *testing.T) {
+		doc := &OpenAPI{
+			Openapi: OpenApiVersion,
+			Info:    Info{Title: "Example API", Version: "1.0.0"},
+			Paths: map[string]PathItem{
+				"/users": {
+					Get: &Operation{Summary: "No responses"},
+					Post: &Operation{
+						Responses: map[string]Response{
+							"201": {},
+						},
+					},
+				},
+			},
+		}
+
+		err := doc.Validate()
+
+		var verrs ValidationErrors
+		if !errors.As(err, &verrs) {
+			t.Fatalf("Expected ValidationErrors, got %v", err)
+		}
+
+		expected := map[string]bool{
+			`$.paths["/users"].get.responses`:                     false,
+			`$.paths["/users"].post.responses["201"].description`: false,
+		}
+		for _, v := range verrs {
+			if _, ok := expected[v.Path]; !ok {
+				t.Errorf("Unexpected violation %q", v)
+				continue
+			}
+			expected[v.Path] = true
+		}
+		for path, found := range expected {
+			if !found {
+				t.Errorf("Expected violation at %s", path)
+			}
+		}
+	})
+}
+
+func TestDefaultResponse(t *testing.T) {
+	t.Run("Route without Out gets the default response", func(t *testing.T) {
+		mux := http.NewServeMux()
+		r := New(mux, "Example API", "1.0.0")
+		r.UseOpenapiDocs(true)
+
+		r.Post("/login", func(w http.ResponseWriter, r *http.Request) {}, Docs{
+			Summary: "Login",
+		})
+
+		op := r.OpenAPI().Paths["/login"].Post
+		if op == nil {
+			t.Fatal("Expected POST operation for /login")
+		}
+
+		res, ok := op.Responses[DefaultResponseCode]
+		if !ok || res.Description == "" {
+			t.Errorf("Expected default response with a description, got %v", op.Responses)
+		}
+
+		if err := r.OpenAPI().Validate(); err != nil {
+			t.Errorf("Expected no validation errors, got %v", err)
+		}
+	})
+
+	t.Run("Custom default response", func(t *testing.T) {
+		mux := http.NewServeMux()
+		r := New(mux, "Example API", "1.0.0")
+		r.UseOpenapiDocs(true)
+		r.UseDefaultResponse("200", &Response{Description: "OK"})
+
+		r.Group("/api", func(api *Router) {
+			api.Get("/ping", func(w http.ResponseWriter, r *http.Request) {}, Docs{
+				Summary: "Ping",
+			})
+		})
+
+		op := r.OpenAPI().Paths["/api/ping"].Get
+		if op == nil || op.Responses["200"].Description != "OK" {
+			t.Errorf("Expected custom 200 response, got %v", op)
+		}
+	})
+
+	t.Run("Disabled default response", func(t *testing.T) {
+		mux := http.NewServeMux()
+		r := New(mux, "Example API", "1.0.0")
+		r.UseOpenapiDocs(true)
+		r.UseDefaultResponse("", nil)
+
+		r.Get("/ping", func(w http.ResponseWriter, r *http.Request) {}, Docs{
+			Summary: "Ping",
+		})
+
+		if op := r.OpenAPI().Paths["/ping"].Get; len(op.Responses) != 0 {
+			t.Errorf("Expected no responses, got %v", op.Responses)
+		}
+	})
+}
+
+func listUsersByID(w http.ResponseWriter, r *http.Request) {}
+
+func TestResponseHeaders(t *testing.T) {
+	mux := http.NewServeMux()
+	r := New(mux, "Example API", "1.0.0")
+	r.UseOpenapiDocs(true)
+	r.UseResponseHeaders(map[string]Header{
+		"X-RateLimit-Limit":     {Description: "Requests allowed in the window.", Schema: &Schema{Type: "integer"}},
+		"X-RateLimit-Remaining": {Description: "Requests left in the window.", Schema: &Schema{Type: "integer"}},
+	})
+
+	r.Get("/users", func(w http.ResponseWriter, r *http.Request) {}, Docs{
+		Responses: map[string]Response{
+			"200": {Description: "OK"},
+			"404": {Description: "Not found"},
+		},
+	})
+	r.Post("/users", func(w http.ResponseWriter, r *http.Request) {}, Docs{Summary: "Create user"})
+
+	doc := r.OpenAPI()
+	for name, responses := range map[string]map[string]Response{
+		"GET /users":  doc.Paths["/users"].Get.Responses,
+		"POST /users": doc.Paths["/users"].Post.Responses,
+	} {
+		for code, res := range responses {
+			for _, header := range []string{"X-RateLimit-Limit", "X-RateLimit-Remaining"} {
+				if _, ok := res.Headers[header]; !ok {
+					t.Errorf("%s: Expected header %s on response %s", name, header, code)
+				}
+			}
+		}
+	}
+}
+
+func TestExampleProvider(t *testing.T) {
+	type User struct {
+		Name string `json:"name"`
+	}
+
+	mux := http.NewServeMux()
+	r := New(mux, "Example API", "1.0.0")
+	r.UseOpenapiDocs(true)
+
+	r.Get("/users/{id}", func(w http.ResponseWriter, r *http.Request) {}, Docs{
+		Out: map[string]DocOut{
+			"200": {ApplicationType: "application/json", Description: "The user.", Object: User{}},
+			"404": {ApplicationType: "application/json", Description: "Not found.", Object: Problem{}},
+		},
+		ExampleProvider: func(status string) any {
+			switch status {
+			case "200":
+				return User{Name: "Jane"}
+			case "404":
+				return Problem{Title: "Not Found", Status: http.StatusNotFound}
+			}
+			return nil
+		},
+	})
+
+	responses := r.OpenAPI().Paths["/users/{id}"].Get.Responses
+	if example := responses["200"].Content["application/json"].Example; example != (User{Name: "Jane"}) {
+		t.Errorf("Expected user example for 200, got %v", example)
+	}
+
+	out, err := json.Marshal(responses["404"].Content["application/json"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(out), `"example":{"title":"Not Found","status":404}`) {
+		t.Errorf("Expected problem example for 404, got %s", out)
+	}
+}
+
+func TestDocExamples(t *testing.T) {
+	type User struct {
+		Name string `json:"name"`
+	}
+
+	mux := http.NewServeMux()
+	r := New(mux, "Example API", "1.0.0")
+	r.UseOpenapiDocs(true)
+
+	r.Post("/users", func(w http.ResponseWriter, r *http.Request) {}, Docs{
+		In: map[string]DocIn{
+			"application/json": {Object: User{}, Example: User{Name: "Jane"}},
+		},
+		Out: map[string]DocOut{
+			"201": {ApplicationType: "application/json", Description: "The user.", Object: User{}, Example: User{Name: "Jane"}},
+			"400": {ApplicationType: "application/problem+json", Description: "Invalid user.", Object: Problem{}},
+		},
+	})
+
+	op := r.OpenAPI().Paths["/users"].Post
+	if example := op.RequestBody.Content["application/json"].Example; example != (User{Name: "Jane"}) {
+		t.Errorf("Expected user example for the request body, got %v", example)
+	}
+
+	out, err := json.Marshal(op.Responses["201"].Content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(out), `"application/json":{"schema":{"$ref":"#/components/schemas/User"},"example":{"name":"Jane"}}`) {
+		t.Errorf("Expected user example for 201, got %s", out)
+	}
+	if example := op.Responses["400"].Content["application/problem+json"].Example; example != nil {
+		t.Errorf("Expected no example for 400, got %v", example)
+	}
+}
+
+func TestNamingStrategy(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy NamingStrategy
+		expected string
+	}{
+		{"Default", NamingStrategy{}, "GETUsersIdPostsPostId"},
+		{"Lower method with param prefix", NamingStrategy{LowerMethod: true, ParamPrefix: "By"}, "getUsersByIdPostsByPostId"},
+		{"Skip params", NamingStrategy{LowerMethod: true, SkipParams: true}, "getUsersPosts"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			r := New(mux, "Example API", "1.0.0")
+			r.UseOpenapiDocs(true)
+			r.UseNamingStrategy(tt.strategy)
+
+			r.Get("/users/{id}/posts/{postId}", func(w http.ResponseWriter, r *http.Request) {}, Docs{})
+
+			if id := r.OpenAPI().Paths["/users/{id}/posts/{postId}"].Get.OperationID; id != tt.expected {
+				t.Errorf("Expected operationId %q, got %q", tt.expected, id)
+			}
+		})
+	}
+}
+
+func TestRequestBodyEncoding(t *testing.T) {
+	type Upload struct {
+		Avatar   string
+		Metadata string
+		Tags     []string
+	}
+
+	mux := http.NewServeMux()
+	r := New(mux, "Example API", "1.0.0")
+	r.UseOpenapiDocs(true)
+
+	explode := false
+	r.Post("/uploads", func(w http.ResponseWriter, r *http.Request) {}, Docs{
+		In: map[string]DocIn{
+			"multipart/form-data": {
+				Object: Upload{},
+				Encoding: map[string]Encoding{
+					"Avatar":   {ContentType: "image/png, image/jpeg"},
+					"Metadata": {ContentType: "application/json"},
+					"Tags":     {Style: "form", Explode: &explode},
+				},
+			},
+		},
+	})
+
+	out, err := json.Marshal(r.OpenAPI().Paths["/uploads"].Post.RequestBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `"encoding":{"Avatar":{"contentType":"image/png, image/jpeg"},"Metadata":{"contentType":"application/json"},"Tags":{"style":"form","explode":false}}`
+	if !strings.Contains(string(out), expected) {
+		t.Errorf("Expected %s in the request body, got %s", expected, out)
+	}
+}
+
+func TestProducesConsumes(t *testing.T) {
+	type User struct {
+		Name string `json:"name"`
+	}
+
+	mux := http.NewServeMux()
+	r := New(mux, "Example API", "1.0.0")
+	r.UseOpenapiDocs(true)
+
+	r.Group("/api", func(api *Router) {
+		api.Produces("application/json")
+		api.Consumes("application/json")
+
+		api.Post("/users", func(w http.ResponseWriter, r *http.Request) {}, Docs{
+			In: map[string]DocIn{
+				"": {Object: User{}},
+			},
+			Out: map[string]DocOut{
+				"201": {Description: "Created", Object: User{}},
+				"400": {ApplicationType: "application/problem+json", Description: "Invalid", Object: Problem{}},
+			},
+		})
+	})
+
+	op := r.OpenAPI().Paths["/api/users"].Post
+	if _, ok := op.RequestBody.Content["application/json"]; !ok || len(op.RequestBody.Content) != 1 {
+		t.Errorf("Expected the request body to default to application/json, got %v", op.RequestBody.Content)
+	}
+	if _, ok := op.Responses["201"].Content["application/json"]; !ok || len(op.Responses["201"].Content) != 1 {
+		t.Errorf("Expected the 201 response to default to application/json, got %v", op.Responses["201"].Content)
+	}
+	if _, ok := op.Responses["400"].Content["application/problem+json"]; !ok || len(op.Responses["400"].Content) != 1 {
+		t.Errorf("Expected the explicit media type to be kept, got %v", op.Responses["400"].Content)
+	}
+}
+
+func TestResetDocs(t *testing.T) {
+	type User struct {
+		Name string `json:"name"`
+	}
+
+	mux := http.NewServeMux()
+	r := New(mux, "Example API", "1.0.0")
+	r.UseOpenapiDocs(true)
+
+	r.Get("/users", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}, Docs{
+		Summary: "Get user",
+		Tags:    []string{"users"},
+		Out: map[string]DocOut{
+			"200": {ApplicationType: "application/json", Description: "The user.", Object: User{}},
+		},
+	})
+
+	r.ResetDocs()
+
+	doc := r.OpenAPI()
+	if len(doc.Paths) != 0 || len(doc.Components.Schemas) != 0 {
+		t.Errorf("Expected an empty spec, got paths %v and schemas %v", doc.Paths, doc.Components.Schemas)
+	}
+
+	if docs := r.GetDocs(); len(docs) != 1 || docs[0].Title != "" || docs[0].Pattern != "/users" {
+		t.Errorf("Expected the route listed without its docs, got %+v", docs)
+	}
+	if routes := r.Routes(); len(routes) != 1 || routes[0].Summary != "" || routes[0].Tags != nil {
+		t.Errorf("Expected the route listed without its summary and tags, got %+v", routes)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/users", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected the route to be served after the reset, got %d", w.Code)
+	}
+}
+
+func TestLegacyDocsFields(t *testing.T) {
+	mux := http.NewServeMux()
+	r := New(mux, "Example API", "1.0.0")
+	r.UseOpenapiDocs(true)
+
+	r.Get("/users/{id}", func(w http.ResponseWriter, r *http.Request) {}, Docs{
+		Title:       "Get user",
+		Description: "Returns a single user.",
+		Params: []DocsParam{
+			{Name: "id", Type: "integer", Description: "The user ID."},
+			{Name: "fields", Description: "Fields to include."},
+		},
+	})
+
+	op := r.OpenAPI().Paths["/users/{id}"].Get
+	if op.Summary != "Get user" {
+		t.Errorf("Expected summary %q, got %q", "Get user", op.Summary)
+	}
+
+	expected := []Parameter{
+		{Name: "id", In: "path", Description: "The user ID.", Required: true, Schema: &Schema{Type: "integer"}},
+		{Name: "fields", In: "query", Description: "Fields to include.", Schema: &Schema{Type: "string"}},
+	}
+	if !reflect.DeepEqual(op.Parameters, expected) {
+		t.Errorf("Expected parameters %+v, got %+v", expected, op.Parameters)
+	}
+}
+
+func TestSecuritySchemes(t *testing.T) {
+	t.Run("Bearer", func(t *testing.T) {
+		r := New(http.NewServeMux(), "Example API", "1.0.0")
+		r.UseBearerAuth("bearerAuth")
+
+		doc := r.OpenAPI()
+		if scheme := doc.Components.SecuritySchemes["bearerAuth"]; scheme != (SecurityScheme{Type: "http", Scheme: "bearer"}) {
+			t.Errorf("Unexpected security scheme %+v", scheme)
+		}
+		if !reflect.DeepEqual(doc.Security, []map[string][]string{{"bearerAuth": {}}}) {
+			t.Errorf("Unexpected global security %v", doc.Security)
+		}
+	})
+
+	t.Run("API key", func(t *testing.T) {
+		r := New(http.NewServeMux(), "Example API", "1.0.0")
+		r.UseAPIKeyAuth("apiKey", "X-API-Key")
+		r.UseAPIKeyAuth("apiKey", "X-API-Key")
+
+		doc := r.OpenAPI()
+		if scheme := doc.Components.SecuritySchemes["apiKey"]; scheme != (SecurityScheme{Type: "apiKey", Name: "X-API-Key", In: "header"}) {
+			t.Errorf("Unexpected security scheme %+v", scheme)
+		}
+		if !reflect.DeepEqual(doc.Security, []map[string][]string{{"apiKey": {}}}) {
+			t.Errorf("Expected the requirement once, got %v", doc.Security)
+		}
+
+		out, err := json.Marshal(doc.Security)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(out) != `[{"apiKey":[]}]` {
+			t.Errorf("Expected an empty scope list, got %s", out)
+		}
+	})
+}
+
+func TestHandlerNamesForDocs(t *testing.T) {
+	t.Run("Derived summary and operationId", func(t *testing.T) {
+		mux := http.NewServeMux()
+		r := New(mux, "Example API", "1.0.0")
+		r.UseOpenapiDocs(true)
+		r.UseHandlerNamesForDocs(true)
+
+		r.Get("/users", listUsersByID)
+		r.Get("/anonymous", func(w http.ResponseWriter, r *http.Request) {})
+
+		op := r.OpenAPI().Paths["/users"].Get
+		if op == nil {
+			t.Fatal("Expected GET operation for /users")
+		}
+		if op.Summary != "List Users By ID" {
+			t.Errorf("Expected summary %q, got %q", "List Users By ID", op.Summary)
+		}
+		if op.OperationID != "listUsersByID" {
+			t.Errorf("Expected operationId %q, got %q", "listUsersByID", op.OperationID)
+		}
+
+		anon := r.OpenAPI().Paths["/anonymous"].Get
+		if anon == nil || anon.Summary != "" || anon.OperationID != "GETAnonymous" {
+			t.Errorf("Expected anonymous handler to fall back to the pattern, got %+v", anon)
+		}
+	})
+
+	t.Run("Explicit docs take precedence", func(t *testing.T) {
+		mux := http.NewServeMux()
+		r := New(mux, "Example API", "1.0.0")
+		r.UseOpenapiDocs(true)
+		r.UseHandlerNamesForDocs(true)
+
+		r.Get("/users", listUsersByID, Docs{
+			Summary:     "User List",
+			OperationID: "getUsers",
+		})
+
+		op := r.OpenAPI().Paths["/users"].Get
+		if op.Summary != "User List" || op.OperationID != "getUsers" {
+			t.Errorf("Expected explicit summary and operationId, got %q %q", op.Summary, op.OperationID)
+		}
+	})
+}
+
+func TestAutoTagByPath(t *testing.T) {
+	mux := http.NewServeMux()
+	r := New(mux, "Example API", "1.0.0")
+	r.UseOpenapiDocs(true)
+	r.AutoTagByPath(true)
+
+	handler := func(w http.ResponseWriter, r *http.Request) {}
+
+	r.Group("/users", func(r *Router) {
+		r.Get("", handler, Docs{Summary: "User List"})
+		r.Get("/{id}", handler, Docs{Summary: "Get User"})
+	})
+	r.Group("/blog", func(r *Router) {
+		r.Get("/{id}", handler, Docs{Summary: "Get Blog"})
+		r.Post("", handler, Docs{Summary: "Create Blog", Tags: []string{"editorial"}})
+	})
+
+	doc := r.OpenAPI()
+	testCases := []struct {
+		op   *Operation
+		tags []string
+	}{
+		{doc.Paths["/users"].Get, []string{"users"}},
+		{doc.Paths["/users/{id}"].Get, []string{"users"}},
+		{doc.Paths["/blog/{id}"].Get, []string{"blog"}},
+		{doc.Paths["/blog"].Post, []string{"editorial"}},
+	}
+
+	for _, tc := range testCases {
+		if !slices.Equal(tc.op.Tags, tc.tags) {
+			t.Errorf("Expected tags %v for %s, got %v", tc.tags, tc.op.OperationID, tc.op.Tags)
+		}
+	}
+
+	var names []string
+	for _, tag := range doc.Tags {
+		names = append(names, tag.Name)
+	}
+	if !slices.Equal(names, []string{"users", "blog"}) {
+		t.Errorf("Expected document tags [users blog], got %v", names)
+	}
+}
+
+func TestOnOpenAPI(t *testing.T) {
+	mux := http.NewServeMux()
+	r := New(mux, "Example API", "1.0.0")
+	r.UseOpenapiDocs(true)
+
+	r.Get("/users", func(w http.ResponseWriter, r *http.Request) {}, Docs{Summary: "User List"})
+
+	r.OnOpenAPI(func(doc *OpenAPI) {
+		op := doc.Paths["/users"].Get
+		if op.Extensions == nil {
+			op.Extensions = make(map[string]any)
+		}
+		op.Extensions["x-internal"] = true
+	})
+	r.OnOpenAPI(func(doc *OpenAPI) {
+		doc.Extensions = map[string]any{"x-logo": map[string]string{"url": "/logo.png"}}
+	})
+
+	out, err := json.Marshal(r.OpenAPI())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded struct {
+		Logo  map[string]string `json:"x-logo"`
+		Paths map[string]struct {
+			Get map[string]any `json:"get"`
+		} `json:"paths"`
+	}
+	if err := json.Unmarshal(out, &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	if decoded.Paths["/users"].Get["x-internal"] != true {
+		t.Errorf("Expected x-internal on the operation, got %s", out)
+	}
+	if decoded.Paths["/users"].Get["summary"] != "User List" {
+		t.Errorf("Expected standard fields next to the extensions, got %s", out)
+	}
+	if decoded.Logo["url"] != "/logo.png" {
+		t.Errorf("Expected x-logo on the root, got %s", out)
+	}
+
+	// the hooks run on a copy, calling OpenAPI again must not stack them
+	if _, err := json.Marshal(r.OpenAPI()); err != nil {
+		t.Fatal(err)
+	}
+	if r.rootParent().openapi.Paths["/users"].Get.Extensions != nil {
+		t.Error("Expected hooks not to modify the registered documentation")
+	}
+}
+
+func TestVendorExtensions(t *testing.T) {
+	t.Run("Operation with x-codeSamples", func(t *testing.T) {
+		mux := http.NewServeMux()
+		r := New(mux, "Example API", "1.0.0")
+		r.UseOpenapiDocs(true)
+
+		r.Get("/users", func(w http.ResponseWriter, r *http.Request) {}, Docs{
+			Summary: "User List",
+			Extensions: map[string]any{
+				"x-codeSamples": []map[string]string{
+					{"lang": "curl", "source": "curl /users"},
+				},
+			},
+		})
+
+		out, err := json.Marshal(r.OpenAPI().Paths["/users"].Get)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var decoded struct {
+			Summary     string              `json:"summary"`
+			CodeSamples []map[string]string `json:"x-codeSamples"`
+		}
+		if err := json.Unmarshal(out, &decoded); err != nil {
+			t.Fatal(err)
+		}
+
+		if decoded.Summary != "User List" || len(decoded.CodeSamples) != 1 || decoded.CodeSamples[0]["lang"] != "curl" {
+			t.Errorf("Expected summary and x-codeSamples, got %s", out)
+		}
+	})
+
+	t.Run("Schema and info extensions", func(t *testing.T) {
+		info := Info{Title: "Example API", Version: "1.0.0", Extensions: map[string]any{"x-audience": "public"}}
+		schema := Schema{Extensions: map[string]any{"x-go-type": "User"}}
+
+		for _, tc := range []struct {
+			value    any
+			expected string
+		}{
+			{info, `{"title":"Example API","version":"1.0.0","x-audience":"public"}`},
+			{schema, `{"x-go-type":"User"}`},
+		} {
+			out, err := json.Marshal(tc.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(out) != tc.expected {
+				t.Errorf("Expected %s, got %s", tc.expected, out)
+			}
+		}
+	})
+
+	t.Run("Invalid extension key", func(t *testing.T) {
+		_, err := json.Marshal(Schema{Type: "string", Extensions: map[string]any{"internal": true}})
+		if err == nil {
+			t.Error("Expected an error for an extension key without the x- prefix")
+		}
+	})
+}
+
+func TestCurlExample(t *testing.T) {
+	type User struct {
+		Name string
+	}
+
+	mux := http.NewServeMux()
+	r := New(mux, "Example API", "1.0.0")
+	r.UseOpenapiDocs(true)
+	r.AddServerEndpoint("http://localhost:3210", "Local")
+
+	handler := func(w http.ResponseWriter, r *http.Request) {}
+
+	r.Post("/teams/{team}/users", handler, Docs{
+		Summary: "Create User",
+		Parameters: []Parameter{
+			{Name: "team", In: "path", Required: true},
+			{Name: "notify", In: "query", Required: true, Schema: &Schema{Type: "boolean", Default: false}},
+			{Name: "X-Tenant", In: "header", Required: true},
+		},
+		In: map[string]DocIn{
+			"application/json": {Object: User{}},
+		},
+	})
+
+	out, err := r.CurlExample(http.MethodPost, "/teams/{team}/users")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `curl -X POST 'http://localhost:3210/teams/<team>/users?notify=false' \
+  -H 'X-Tenant: <X-Tenant>' \
+  -H 'Content-Type: application/json' \
+  -d '{"Name":"string"}'`
+	if out != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, out)
+	}
+
+	if _, err := r.CurlExample(http.MethodDelete, "/teams/{team}/users"); err == nil {
+		t.Error("Expected an error for an undocumented operation")
+	}
+
+	nullable := Schema{AllOf: []Schema{{Ref: "#/components/schemas/Address"}}, Nullable: true}
+	schemas := map[string]Schema{"Address": {Type: "object", Properties: map[string]Schema{"city": {Type: "string"}}}}
+	if got, want := exampleFromSchema(&nullable, schemas, 0), map[string]any{"city": "string"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected the example of a nullable reference to be %v, got %v", want, got)
+	}
+
+	status := Schema{Type: "string", Enum: []any{"draft", "published"}}
+	if got := exampleFromSchema(&status, nil, 0); got != "draft" {
+		t.Errorf("Expected the example of an enum to be its first value, got %v", got)
+	}
+}
+
+func TestInline(t *testing.T) {
+	type Address struct {
+		City string `json:"city"`
+	}
+
+	mux := http.NewServeMux()
+	r := New(mux, "Example API", "1.0.0")
+	r.UseOpenapiDocs(true)
+
+	r.Get("/addresses", func(w http.ResponseWriter, r *http.Request) {}, Docs{
+		Out: map[string]DocOut{
+			"200": {
+				ApplicationType: "application/json",
+				Description:     "The address.",
+				Object:          Address{},
+			},
+		},
+	})
+
+	t.Run("Acyclic schema", func(t *testing.T) {
+		out, err := json.Marshal(r.OpenAPI().Inline())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if strings.Contains(string(out), "$ref") {
+			t.Errorf("Expected no $ref in the inlined document, got %s", out)
+		}
+
+		schema := r.OpenAPI().Inline().Paths["/addresses"].Get.Responses["200"].Content["application/json"].Schema
+		if schema.Type != "object" || schema.Properties["city"].Type != "string" {
+			t.Errorf("Expected inlined address, got %+v", schema)
+		}
+
+		if _, ok := r.OpenAPI().Components.Schemas["Address"]; !ok {
+			t.Error("Expected the original document to keep its components")
+		}
+	})
+
+	t.Run("Cyclic schema keeps a reference", func(t *testing.T) {
+		doc := r.OpenAPI().Clone()
+		doc.Components.Schemas["Node"] = Schema{
+			Type: "object",
+			Properties: map[string]Schema{
+				"next": {Ref: "#/components/schemas/Node"},
+			},
+		}
+		doc.Paths["/nodes"] = PathItem{
+			Get: &Operation{
+				Responses: map[string]Response{
+					"200": {
+						Description: "A node.",
+						Content: map[string]MediaType{
+							"application/json": {Schema: &Schema{Ref: "#/components/schemas/Node"}},
+						},
+					},
+				},
+			},
+		}
+
+		inlined := doc.Inline()
+		schema := inlined.Paths["/nodes"].Get.Responses["200"].Content["application/json"].Schema
+		if schema.Type != "object" || schema.Properties["next"].Ref != "#/components/schemas/Node" {
+			t.Errorf("Expected the recursive property to keep its reference, got %+v", schema)
+		}
+		if _, ok := inlined.Components.Schemas["Node"]; !ok {
+			t.Error("Expected components to be kept for cyclic schemas")
+		}
+	})
+}
+
+func TestInlineSchemas(t *testing.T) {
+	type Address struct {
+		City string `json:"city"`
+	}
+
+	mux := http.NewServeMux()
+	r := New(mux, "Example API", "1.0.0")
+	r.UseOpenapiDocs(true)
+	r.InlineSchemas(true)
+
+	r.Get("/addresses", func(w http.ResponseWriter, r *http.Request) {}, Docs{
+		Out: map[string]DocOut{
+			"200": {
+				ApplicationType: "application/json",
+				Description:     "The address.",
+				Object:          Address{},
+			},
+		},
+	})
+
+	schema := r.OpenAPI().Paths["/addresses"].Get.Responses["200"].Content["application/json"].Schema
+	if schema.Ref != "" {
+		t.Errorf("Expected no $ref, got %q", schema.Ref)
+	}
+	if schema.Type != "object" || schema.Properties["city"].Type != "string" {
+		t.Errorf("Expected the address schema to be embedded, got %+v", schema)
+	}
+
+	if _, ok := r.OpenAPI().Components.Schemas["Address"]; ok {
+		t.Error("Expected no component schema in inline mode")
+	}
+}
+
+func TestRouteBuilder(t *testing.T) {
+	type User struct {
+		Name string `json:"name"`
+	}
+
+	mux := http.NewServeMux()
+	r := New(mux, "Example API", "1.0.0")
+	r.UseOpenapiDocs(true)
+
+	r.Group("/users", func(r *Router) {
+		r.Put("/{id}", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}).
+			Summary("Update user").
+			Description("Updates an existing user.").
+			Tag("users").
+			Param(Parameter{Name: "id", In: "path", Required: true, Schema: &Schema{Type: "string"}}).
+			Body(User{}).
+			Response(http.StatusOK, User{}).
+			Response(http.StatusNotFound, nil, "The user does not exist.")
+	})
+
+	op := r.OpenAPI().Paths["/users/{id}"].Put
+	if op == nil {
+		t.Fatal("Expected PUT operation for /users/{id}")
+	}
+
+	if op.Summary != "Update user" || op.Description != "Updates an existing user." {
+		t.Errorf("Unexpected summary or description: %q %q", op.Summary, op.Description)
+	}
+	if !slices.Equal(op.Tags, []string{"users"}) {
+		t.Errorf("Expected tags [users], got %v", op.Tags)
+	}
+	if len(op.Parameters) != 1 || op.Parameters[0].Name != "id" {
+		t.Errorf("Expected id parameter, got %+v", op.Parameters)
+	}
+	if op.RequestBody == nil || op.RequestBody.Content["application/json"].Schema.Ref != "#/components/schemas/User" {
+		t.Errorf("Expected User request body, got %+v", op.RequestBody)
+	}
+	if op.Responses["200"].Content["application/json"].Schema.Ref != "#/components/schemas/User" {
+		t.Errorf("Expected User response, got %+v", op.Responses["200"])
+	}
+	if op.Responses["404"].Description != "The user does not exist." {
+		t.Errorf("Expected 404 response, got %+v", op.Responses["404"])
+	}
+	if _, ok := op.Responses[DefaultResponseCode]; ok {
+		t.Error("Expected the default response to be replaced by the documented responses")
+	}
+
+	req := httptest.NewRequest(http.MethodPut, "/users/1", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+}
+
+func TestAcceptedLinks(t *testing.T) {
+	mux := http.NewServeMux()
+	r := New(mux, "Example API", "1.0.0")
+	r.UseOpenapiDocs(true)
+
+	r.Get("/jobs/{id}", func(w http.ResponseWriter, r *http.Request) {}).OperationID("getJob")
+	r.Post("/jobs", func(w http.ResponseWriter, r *http.Request) {}).
+		Accepted("getJob", map[string]any{"id": "$response.body#/id"})
+
+	res := r.OpenAPI().Paths["/jobs"].Post.Responses["202"]
+	if _, ok := res.Headers["Location"]; !ok {
+		t.Fatalf("Expected Location header on 202 response, got %v", res.Headers)
+	}
+	if res.Content != nil {
+		t.Errorf("Expected no content on 202 response, got %v", res.Content)
+	}
+
+	b, err := json.Marshal(res)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `"links":{"status":{"operationId":"getJob","parameters":{"id":"$response.body#/id"}}}`
+	if !strings.Contains(string(b), want) {
+		t.Errorf("Expected %s in %s", want, b)
+	}
+}
+
+func TestInfoSummary(t *testing.T) {
+	defer func(version string) { OpenApiVersion = version }(OpenApiVersion)
+
+	description := "# Example API\n\nUse `a & b` with <b>care</b>:\n\n- one\n- \"two\"\n"
+
+	for _, tt := range []struct {
+		version string
+		summary bool
+	}{
+		{"3.0.1", false},
+		{"3.1.0", true},
+	} {
+		OpenApiVersion = tt.version
+
+		r := New(http.NewServeMux(), "Example API", "1.0.0")
+		r.SetInfoSummary("An example")
+		r.OpenAPI().Info.Description = description
+
+		var buf strings.Builder
+		enc := json.NewEncoder(&buf)
+		enc.SetEscapeHTML(false)
+		if err := enc.Encode(r.OpenAPI()); err != nil {
+			t.Fatal(err)
+		}
+
+		if got := strings.Contains(buf.String(), `"summary":"An example"`); got != tt.summary {
+			t.Errorf("%s: Expected summary %v in %s", tt.version, tt.summary, buf.String())
+		}
+		if !strings.Contains(buf.String(), "<b>care</b>") || !strings.Contains(buf.String(), "a & b") {
+			t.Errorf("%s: Expected unescaped markdown in %s", tt.version, buf.String())
+		}
+
+		var doc OpenAPI
+		if err := json.Unmarshal([]byte(buf.String()), &doc); err != nil {
+			t.Fatal(err)
+		}
+		if doc.Info.Description != description {
+			t.Errorf("%s: Expected description %q, got %q", tt.version, description, doc.Info.Description)
+		}
+	}
+}
+
+func TestGetWithSpec(t *testing.T) {
+	mux := http.NewServeMux()
+	r := New(mux, "Example API", "1.0.0")
+	r.UseOpenapiDocs(true)
+
+	r.GetWithSpec("/reports/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}, []byte(`{
+		"tags": ["reports"],
+		"summary": "Get report",
+		"operationId": "getReport",
+		"parameters": [{"name": "id", "in": "path", "required": true, "schema": {"type": "string"}}],
+		"responses": {
+			"200": {"description": "The report.", "content": {"text/csv": {"schema": {"type": "string"}}}}
+		},
+		"x-internal": true
+	}`))
+
+	op := r.OpenAPI().Paths["/reports/{id}"].Get
+	if op == nil {
+		t.Fatal("Expected GET operation for /reports/{id}")
+	}
+	if op.Summary != "Get report" || op.OperationID != "getReport" || !slices.Equal(op.Tags, []string{"reports"}) {
+		t.Errorf("Unexpected operation %+v", op)
+	}
+	if len(op.Parameters) != 1 || op.Parameters[0].Name != "id" || !op.Parameters[0].Required {
+		t.Errorf("Unexpected parameters %+v", op.Parameters)
+	}
+	if _, ok := op.Responses["200"].Content["text/csv"]; !ok {
+		t.Errorf("Expected a text/csv response, got %+v", op.Responses)
+	}
+	if op.Extensions["x-internal"] != true {
+		t.Errorf("Expected the x-internal extension, got %v", op.Extensions)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/reports/1", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", w.Code)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected a panic on an invalid fragment")
+		}
+	}()
+	r.GetWithSpec("/broken", func(w http.ResponseWriter, r *http.Request) {}, []byte(`{"summary":`))
+}
+
+func TestGetWithSpecDeprecatedServers(t *testing.T) {
+	mux := http.NewServeMux()
+	r := New(mux, "Example API", "1.0.0")
+	r.UseOpenapiDocs(true)
+
+	spec := []byte(`{
+		"summary": "Get legacy report",
+		"deprecated": true,
+		"servers": [{"url": "https://legacy.example.com"}],
+		"responses": {"200": {"description": "The report."}}
+	}`)
+	r.GetWithSpec("/legacy/{id}", func(w http.ResponseWriter, r *http.Request) {}, spec)
+	r.Host("api.example.com").GetWithSpec("/hosted/{id}", func(w http.ResponseWriter, r *http.Request) {}, spec)
+
+	want := []Server{{URL: "https://legacy.example.com"}}
+	for _, path := range []string{"/legacy/{id}", "/hosted/{id}"} {
+		op := r.OpenAPI().Paths[path].Get
+		if op == nil {
+			t.Fatalf("Expected GET operation for %s", path)
+		}
+		if !op.Deprecated {
+			t.Errorf("Expected %s to be deprecated", path)
+		}
+		if !reflect.DeepEqual(op.Servers, want) {
+			t.Errorf("Expected %s servers %v, got %v", path, want, op.Servers)
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/legacy/1", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Header().Get("Deprecation") != "true" {
+		t.Errorf("Expected a Deprecation header, got %q", w.Header().Get("Deprecation"))
+	}
+}
+
+func TestOpenAPIFiltered(t *testing.T) {
+	type User struct {
+		Name string `json:"name"`
+	}
+	type AuditEntry struct {
+		Action string `json:"action"`
+		User   User   `json:"user"`
+	}
+
+	mux := http.NewServeMux()
+	r := New(mux, "Example API", "1.0.0")
+	r.UseOpenapiDocs(true)
+
+	handler := func(w http.ResponseWriter, r *http.Request) {}
+	r.Get("/users", handler).Tag("users").Response(http.StatusOK, []User{})
+	r.Get("/audit", handler).Tag("internal").Response(http.StatusOK, []AuditEntry{})
+	r.Delete("/users/{id}", handler).Tag("users", "internal").Response(http.StatusNoContent, nil)
+
+	public := func(op *Operation) bool { return !slices.Contains(op.Tags, "internal") }
+	r.ServeOpenAPIFiltered("/openapi.public.json", public)
+	r.ServeOpenAPI("/openapi.json")
+
+	req := httptest.NewRequest(http.MethodGet, "/openapi.public.json", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	var doc OpenAPI
+	if err := json.NewDecoder(w.Body).Decode(&doc); err != nil {
+		t.Fatal(err)
+	}
+
+	if doc.Paths["/users"].Get == nil {
+		t.Error("Expected the public document to keep GET /users")
+	}
+	if _, ok := doc.Paths["/audit"]; ok {
+		t.Error("Expected the public document to omit /audit")
+	}
+	if _, ok := doc.Paths["/users/{id}"]; ok {
+		t.Error("Expected the public document to omit /users/{id}")
+	}
+	if _, ok := doc.Components.Schemas["User"]; !ok {
+		t.Error("Expected the public document to keep the User schema")
+	}
+	if _, ok := doc.Components.Schemas["AuditEntry"]; ok {
+		t.Error("Expected the public document to omit the AuditEntry schema")
+	}
+
+	full := r.OpenAPI()
+	if full.Paths["/audit"].Get == nil || full.Paths["/users/{id}"].Delete == nil {
+		t.Error("Expected the full document to be left untouched")
+	}
+	if _, ok := full.Components.Schemas["AuditEntry"]; !ok {
+		t.Error("Expected the full document to keep the AuditEntry schema")
+	}
+}
+
+func TestJSONSchemaDialect(t *testing.T) {
+	defer func(version string) { OpenApiVersion = version }(OpenApiVersion)
+
+	type Profile struct {
+		Bio     *string      `json:"bio"`
+		Address *testAddress `json:"address"`
+	}
+
+	for _, tt := range []struct {
+		version string
+		want    []string
+		absent  []string
+	}{
+		{
+			version: "3.0.1",
+			want: []string{
+				`"bio":{"type":"string","nullable":true}`,
+				`"address":{"allOf":[{"$ref":"#/components/schemas/testAddress"}],"nullable":true}`,
+			},
+			absent: []string{`"jsonSchemaDialect"`},
+		},
+		{
+			version: "3.1.0",
+			want: []string{
+				`"jsonSchemaDialect":"https://json-schema.org/draft/2020-12/schema"`,
+				`"bio":{"type":["string","null"]}`,
+				`"address":{"oneOf":[{"$ref":"#/components/schemas/testAddress"},{"type":"null"}]}`,
+			},
+			absent: []string{`"nullable"`},
+		},
+	} {
+		OpenApiVersion = tt.version
+
+		r := New(http.NewServeMux(), "Example API", "1.0.0")
+		r.UseOpenapiDocs(true)
+		r.SetJSONSchemaDialect("https://json-schema.org/draft/2020-12/schema")
+		r.Get("/profile", func(w http.ResponseWriter, r *http.Request) {}).Response(http.StatusOK, Profile{})
+
+		b, err := json.Marshal(r.OpenAPI())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, want := range tt.want {
+			if !strings.Contains(string(b), want) {
+				t.Errorf("%s: Expected %s in %s", tt.version, want, b)
+			}
+		}
+		for _, absent := range tt.absent {
+			if strings.Contains(string(b), absent) {
+				t.Errorf("%s: Expected no %s in %s", tt.version, absent, b)
+			}
+		}
+
+		if !r.OpenAPI().Components.Schemas["Profile"].Properties["bio"].Nullable {
+			t.Errorf("%s: Expected the document to remain unchanged by encoding", tt.version)
+		}
+	}
+}
+
+func TestDeprecate(t *testing.T) {
+	mux := http.NewServeMux()
+	r := New(mux, "Example API", "1.0.0")
+	r.UseOpenapiDocs(true)
+
+	sunset := time.Date(2027, time.January, 1, 0, 0, 0, 0, time.UTC)
+	handler := func(w http.ResponseWriter, r *http.Request) {}
+	r.Get("/v1/users", handler).Response(http.StatusOK, nil).Deprecate(sunset)
+	r.Get("/v2/users", handler).Response(http.StatusOK, nil)
+
+	doc := r.OpenAPI()
+	if !doc.Paths["/v1/users"].Get.Deprecated {
+		t.Error("Expected /v1/users to be deprecated")
+	}
+	if doc.Paths["/v2/users"].Get.Deprecated {
+		t.Error("Expected /v2/users not to be deprecated")
+	}
+
+	for _, tt := range []struct {
+		path        string
+		deprecation string
+		sunset      string
+	}{
+		{"/v1/users", "true", "Fri, 01 Jan 2027 00:00:00 GMT"},
+		{"/v2/users", "", ""},
+	} {
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+
+		if got := rr.Header().Get("Deprecation"); got != tt.deprecation {
+			t.Errorf("%s: Expected Deprecation %q, got %q", tt.path, tt.deprecation, got)
+		}
+		if got := rr.Header().Get("Sunset"); got != tt.sunset {
+			t.Errorf("%s: Expected Sunset %q, got %q", tt.path, tt.sunset, got)
+		}
+	}
+}
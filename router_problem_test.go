@@ -0,0 +1,77 @@
+package router
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestProblem(t *testing.T) {
+	mux := http.NewServeMux()
+	r := New(mux, "Example API", "1.0.0")
+
+	r.HandleStatus(http.StatusNotFound, ProblemHandler(http.StatusNotFound))
+
+	r.Post("/orders", func(w http.ResponseWriter, req *http.Request) {
+		_ = WriteProblem(w, Problem{
+			Type:   "https://example.com/problems/out-of-stock",
+			Title:  "Out of stock",
+			Status: http.StatusConflict,
+			Detail: "Item 42 is no longer available.",
+		})
+	})
+
+	testCases := []struct {
+		name     string
+		method   string
+		path     string
+		expected Problem
+	}{
+		{
+			name:   "Handler problem",
+			method: http.MethodPost,
+			path:   "/orders",
+			expected: Problem{
+				Type:   "https://example.com/problems/out-of-stock",
+				Title:  "Out of stock",
+				Status: http.StatusConflict,
+				Detail: "Item 42 is no longer available.",
+			},
+		},
+		{
+			name:   "Status handler problem",
+			method: http.MethodGet,
+			path:   "/missing",
+			expected: Problem{
+				Type:     "about:blank",
+				Title:    "Not Found",
+				Status:   http.StatusNotFound,
+				Instance: "/missing",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.path, nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tc.expected.Status {
+				t.Errorf("Expected status %d, got %d", tc.expected.Status, w.Code)
+			}
+			if ct := w.Header().Get("Content-Type"); ct != "application/problem+json" {
+				t.Errorf("Expected Content-Type application/problem+json, got %q", ct)
+			}
+
+			var p Problem
+			if err := json.Unmarshal(w.Body.Bytes(), &p); err != nil {
+				t.Fatal(err)
+			}
+			if p != tc.expected {
+				t.Errorf("Expected %+v, got %+v", tc.expected, p)
+			}
+		})
+	}
+}
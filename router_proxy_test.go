@@ -0,0 +1,66 @@
+package router
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestProxy(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/v1/missing" {
+			http.Error(w, "backend not found", http.StatusNotFound)
+			return
+		}
+		fmt.Fprintf(w, "%s %s %s %s", req.Method, req.URL.Path, req.Host, req.Header.Get("X-Gateway"))
+	}))
+	defer backend.Close()
+
+	mux := http.NewServeMux()
+	r := New(mux, "Example API", "1.0.0")
+	r.HandleStatus(http.StatusNotFound, func(w http.ResponseWriter, req *http.Request) {
+		http.Error(w, "router not found", http.StatusNotFound)
+	})
+
+	err := r.Proxy("/api", backend.URL+"/v1", ProxyOptions{
+		Headers: http.Header{"X-Gateway": {"go-router"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	t.Run("Forwards to the backend", func(t *testing.T) {
+		res, err := http.Post(ts.URL+"/api/users", "text/plain", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer res.Body.Close()
+
+		body, _ := io.ReadAll(res.Body)
+		expected := fmt.Sprintf("POST /v1/users %s go-router", backend.Listener.Addr())
+		if string(body) != expected {
+			t.Errorf("Expected %q, got %q", expected, body)
+		}
+	})
+
+	t.Run("Backend statuses are not intercepted", func(t *testing.T) {
+		res, err := http.Get(ts.URL + "/api/missing")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer res.Body.Close()
+
+		body, _ := io.ReadAll(res.Body)
+		if res.StatusCode != http.StatusNotFound || string(body) != "backend not found\n" {
+			t.Errorf("Expected the backend's 404, got %d %q", res.StatusCode, body)
+		}
+		if res.Header.Get(HeaderFlagDoNotIntercept) != "" {
+			t.Error("Expected the interception flag to be removed from the response")
+		}
+	})
+}
@@ -0,0 +1,596 @@
+package router
+
+import (
+	"encoding/json"
+	"maps"
+	"net/http"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+)
+
+type (
+	testPayload interface {
+		isPayload()
+	}
+
+	UserCreated struct {
+		Name string `json:"name"`
+	}
+
+	UserDeleted struct {
+		ID int `json:"id"`
+	}
+
+	Event struct {
+		Payload testPayload `json:"payload"`
+		Meta    any         `json:"meta"`
+	}
+)
+
+func (UserCreated) isPayload()  {}
+func (*UserDeleted) isPayload() {}
+
+type testPage[T any] struct {
+	Items []T `json:"items"`
+}
+
+func TestInterfaceSchema(t *testing.T) {
+	mux := http.NewServeMux()
+	r := New(mux, "Example API", "1.0.0")
+	r.UseOpenapiDocs(true)
+
+	r.RegisterInterfaceImpl(reflect.TypeOf((*testPayload)(nil)).Elem(), reflect.TypeOf(UserCreated{}), reflect.TypeOf(&UserDeleted{}))
+
+	r.Get("/events", func(w http.ResponseWriter, r *http.Request) {}, Docs{
+		Out: map[string]DocOut{
+			"200": {
+				ApplicationType: "application/json",
+				Description:     "An event.",
+				Object:          Event{},
+			},
+		},
+	})
+
+	schemas := r.OpenAPI().Components.Schemas
+
+	payload := schemas["Event"].Properties["payload"]
+	if len(payload.OneOf) != 2 ||
+		payload.OneOf[0].Ref != "#/components/schemas/UserCreated" ||
+		payload.OneOf[1].Ref != "#/components/schemas/UserDeleted" {
+		t.Errorf("Expected payload oneOf UserCreated and UserDeleted, got %+v", payload)
+	}
+
+	if meta := schemas["Event"].Properties["meta"]; !reflect.DeepEqual(meta, Schema{}) {
+		t.Errorf("Expected an empty schema for an unregistered interface, got %+v", meta)
+	}
+
+	if schemas["UserCreated"].Properties["name"].Type != "string" {
+		t.Errorf("Expected UserCreated component schema, got %+v", schemas["UserCreated"])
+	}
+	if schemas["UserDeleted"].Properties["id"].Type != "integer" {
+		t.Errorf("Expected UserDeleted component schema, got %+v", schemas["UserDeleted"])
+	}
+}
+
+type testUUID [16]byte
+
+type testDecimal struct {
+	value string
+}
+
+func TestRegisterTypeSchema(t *testing.T) {
+	type Invoice struct {
+		ID     testUUID     `json:"id"`
+		Amount testDecimal  `json:"amount"`
+		Parent *testUUID    `json:"parent"`
+		Total  *testDecimal `json:"total"`
+	}
+
+	mux := http.NewServeMux()
+	r := New(mux, "Example API", "1.0.0")
+	r.UseOpenapiDocs(true)
+
+	r.RegisterTypeSchema(reflect.TypeOf(testUUID{}), Schema{Type: "string", Format: "uuid"})
+	r.RegisterTypeSchema(reflect.TypeOf(testDecimal{}), Schema{Type: "string"})
+
+	r.Get("/invoices", func(w http.ResponseWriter, r *http.Request) {}, Docs{
+		Out: map[string]DocOut{
+			"200": {ApplicationType: "application/json", Description: "The invoice.", Object: Invoice{}},
+		},
+	})
+
+	props := r.OpenAPI().Components.Schemas["Invoice"].Properties
+	for _, name := range []string{"id", "parent"} {
+		if s := props[name]; s.Type != "string" || s.Format != "uuid" {
+			t.Errorf("Expected %s to be a uuid string, got %+v", name, s)
+		}
+	}
+	for _, name := range []string{"amount", "total"} {
+		if s := props[name]; s.Type != "string" || s.Format != "" {
+			t.Errorf("Expected %s to be a plain string, got %+v", name, s)
+		}
+	}
+}
+
+type testEmployee struct {
+	Name    string          `json:"name"`
+	Address testAddress     `json:"address"`
+	Manager *testEmployee   `json:"manager"`
+	Reports []testEmployee  `json:"reports"`
+	Offices []*testAddress  `json:"offices"`
+	Meta    struct{ N int } `json:"meta"`
+	secret  string
+	Ignored string `json:"-"`
+}
+
+type testAddress struct {
+	City string `json:"city"`
+}
+
+func TestNestedStructSchema(t *testing.T) {
+	mux := http.NewServeMux()
+	r := New(mux, "Example API", "1.0.0")
+	r.UseOpenapiDocs(true)
+
+	r.Get("/employees/{id}", func(w http.ResponseWriter, r *http.Request) {}, Docs{
+		Out: map[string]DocOut{
+			"200": {ApplicationType: "application/json", Description: "The employee.", Object: testEmployee{}},
+		},
+	})
+
+	schemas := r.OpenAPI().Components.Schemas
+	if city := schemas["testAddress"].Properties["city"]; city.Type != "string" {
+		t.Errorf("Expected testAddress component with a city string, got %+v", schemas["testAddress"])
+	}
+
+	props := schemas["testEmployee"].Properties
+	ref := func(name string) string { return "#/components/schemas/" + name }
+
+	if props["address"].Ref != ref("testAddress") {
+		t.Errorf("Expected address to reference testAddress, got %+v", props["address"])
+	}
+	if s := props["manager"]; len(s.AllOf) != 1 || s.AllOf[0].Ref != ref("testEmployee") {
+		t.Errorf("Expected manager to reference testEmployee, got %+v", props["manager"])
+	}
+	if s := props["reports"]; s.Type != "array" || s.Items == nil || s.Items.Ref != ref("testEmployee") {
+		t.Errorf("Expected reports to be an array of testEmployee, got %+v", s)
+	}
+	if s := props["offices"]; s.Type != "array" || s.Items == nil || s.Items.Ref != ref("testAddress") {
+		t.Errorf("Expected offices to be an array of testAddress, got %+v", s)
+	}
+	if s := props["meta"]; s.Type != "object" || s.Properties["N"].Type != "integer" {
+		t.Errorf("Expected meta to be an inline object, got %+v", s)
+	}
+	for _, name := range []string{"secret", "Ignored", "-"} {
+		if _, ok := props[name]; ok {
+			t.Errorf("Expected %s to be skipped", name)
+		}
+	}
+}
+
+func TestTimeSchema(t *testing.T) {
+	type Event struct {
+		Name      string     `json:"name"`
+		CreatedAt time.Time  `json:"createdAt"`
+		DeletedAt *time.Time `json:"deletedAt"`
+	}
+	type NewEvent struct {
+		At time.Time
+	}
+
+	mux := http.NewServeMux()
+	r := New(mux, "Example API", "1.0.0")
+	r.UseOpenapiDocs(true)
+
+	r.Post("/events", func(w http.ResponseWriter, r *http.Request) {}, Docs{
+		In: map[string]DocIn{
+			"application/json": {Object: NewEvent{}},
+		},
+		Out: map[string]DocOut{
+			"201": {ApplicationType: "application/json", Description: "The event.", Object: Event{}},
+		},
+	})
+
+	schemas := r.OpenAPI().Components.Schemas
+	for _, s := range []Schema{
+		schemas["Event"].Properties["createdAt"],
+		schemas["Event"].Properties["deletedAt"],
+		schemas["NewEvent"].Properties["At"],
+	} {
+		if s.Type != "string" || s.Format != "date-time" || len(s.Properties) > 0 {
+			t.Errorf("Expected a date-time string, got %+v", s)
+		}
+	}
+	if _, ok := schemas["Time"]; ok {
+		t.Error("Expected no component schema for time.Time")
+	}
+}
+
+func TestRequiredFromTags(t *testing.T) {
+	type Signup struct {
+		Email    string `json:"email" validate:"required,email"`
+		Password string `json:"password" openapi:"required"`
+		Nickname string `json:"nickname,omitempty" validate:"required"`
+		Invite   string `json:"invite,omitempty" openapi:"required"`
+		Referrer string `json:"referrer"`
+	}
+	type NewSignup struct {
+		Email    string `validate:"required"`
+		Referrer string
+	}
+
+	mux := http.NewServeMux()
+	r := New(mux, "Example API", "1.0.0")
+	r.UseOpenapiDocs(true)
+
+	r.Post("/signups", func(w http.ResponseWriter, r *http.Request) {}, Docs{
+		In: map[string]DocIn{
+			"application/json": {Object: NewSignup{}},
+		},
+		Out: map[string]DocOut{
+			"201": {ApplicationType: "application/json", Description: "The signup.", Object: Signup{}},
+		},
+	})
+
+	schemas := r.OpenAPI().Components.Schemas
+	if got, want := schemas["Signup"].Required, []string{"email", "password", "invite"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected required %v, got %v", want, got)
+	}
+	if got, want := schemas["NewSignup"].Required, []string{"Email"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected required %v, got %v", want, got)
+	}
+}
+
+func TestFormatTag(t *testing.T) {
+	type Account struct {
+		ID      string   `json:"id" openapi:"format=uuid"`
+		Email   string   `json:"email" openapi:"required,format=email"`
+		Born    string   `json:"born" openapi:"format=date"`
+		Balance int64    `json:"balance"`
+		Rate    float32  `json:"rate"`
+		Score   float64  `json:"score"`
+		Count   int      `json:"count"`
+		Members []string `json:"members" openapi:"format=uuid"`
+	}
+	type NewAccount struct {
+		ID      string `openapi:"format=uuid"`
+		Balance int64
+	}
+
+	mux := http.NewServeMux()
+	r := New(mux, "Example API", "1.0.0")
+	r.UseOpenapiDocs(true)
+
+	r.Post("/accounts", func(w http.ResponseWriter, r *http.Request) {}, Docs{
+		In: map[string]DocIn{
+			"application/json": {Object: NewAccount{}},
+		},
+		Out: map[string]DocOut{
+			"201": {ApplicationType: "application/json", Description: "The account.", Object: Account{}},
+		},
+	})
+
+	schemas := r.OpenAPI().Components.Schemas
+	for name, format := range map[string]string{
+		"id":      "uuid",
+		"email":   "email",
+		"born":    "date",
+		"balance": "int64",
+		"rate":    "float",
+		"score":   "double",
+		"count":   "",
+	} {
+		if got := schemas["Account"].Properties[name].Format; got != format {
+			t.Errorf("Expected %s format %q, got %q", name, format, got)
+		}
+	}
+	if items := schemas["Account"].Properties["members"].Items; items == nil || items.Format != "uuid" {
+		t.Errorf("Expected members items format uuid, got %+v", items)
+	}
+	for name, format := range map[string]string{"ID": "uuid", "Balance": "int64"} {
+		if got := schemas["NewAccount"].Properties[name].Format; got != format {
+			t.Errorf("Expected request %s format %q, got %q", name, format, got)
+		}
+	}
+}
+
+func TestEnumTag(t *testing.T) {
+	type Post struct {
+		Status   string `json:"status" openapi:"required,enum=draft|published|archived"`
+		Priority int    `json:"priority" openapi:"enum=1|2|3"`
+		Labels   []int  `json:"labels" openapi:"enum=4|5"`
+	}
+
+	mux := http.NewServeMux()
+	r := New(mux, "Example API", "1.0.0")
+	r.UseOpenapiDocs(true)
+
+	r.Get("/posts/{id}", func(w http.ResponseWriter, r *http.Request) {}).
+		Response(http.StatusOK, Post{})
+
+	props := r.OpenAPI().Components.Schemas["Post"].Properties
+	if got, want := props["status"].Enum, []any{"draft", "published", "archived"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected status enum %v, got %v", want, got)
+	}
+	if got, want := props["priority"].Enum, []any{int64(1), int64(2), int64(3)}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected priority enum %v, got %v", want, got)
+	}
+	if items := props["labels"].Items; items == nil || !reflect.DeepEqual(items.Enum, []any{int64(4), int64(5)}) || props["labels"].Enum != nil {
+		t.Errorf("Expected the labels enum on the items, got %+v", props["labels"])
+	}
+
+	t.Run("Mistyped values", func(t *testing.T) {
+		type Task struct {
+			Priority int `json:"priority" openapi:"enum=1|high"`
+		}
+
+		defer func() {
+			if recover() == nil {
+				t.Error("Expected a panic for an enum value that is not an integer")
+			}
+		}()
+		r.Get("/tasks/{id}", func(w http.ResponseWriter, r *http.Request) {}).
+			Response(http.StatusOK, Task{})
+	})
+}
+
+func TestPointerFieldsNullable(t *testing.T) {
+	type Team struct {
+		Name    string        `json:"name" validate:"required"`
+		Lead    *testEmployee `json:"lead" validate:"required"`
+		Budget  *int          `json:"budget" openapi:"required"`
+		Parent  *Team         `json:"parent"`
+		Members []testAddress `json:"members"`
+	}
+
+	mux := http.NewServeMux()
+	r := New(mux, "Example API", "1.0.0")
+	r.UseOpenapiDocs(true)
+
+	r.Get("/teams/{id}", func(w http.ResponseWriter, r *http.Request) {}).
+		Response(http.StatusOK, Team{})
+
+	team := r.OpenAPI().Components.Schemas["Team"]
+	if s := team.Properties["lead"]; len(s.AllOf) != 1 || s.AllOf[0].Ref != "#/components/schemas/testEmployee" || s.Ref != "" || !s.Nullable {
+		t.Errorf("Expected lead to be a nullable testEmployee reference, got %+v", s)
+	}
+	if s := team.Properties["budget"]; s.Type != "integer" || !s.Nullable {
+		t.Errorf("Expected budget to be a nullable integer, got %+v", s)
+	}
+	if s := team.Properties["parent"]; len(s.AllOf) != 1 || s.AllOf[0].Ref != "#/components/schemas/Team" || s.Ref != "" || !s.Nullable {
+		t.Errorf("Expected parent to be a nullable Team reference, got %+v", s)
+	}
+	for _, name := range []string{"name", "members"} {
+		if team.Properties[name].Nullable {
+			t.Errorf("Expected %s not to be nullable", name)
+		}
+	}
+	if want := []string{"name"}; !reflect.DeepEqual(team.Required, want) {
+		t.Errorf("Expected required %v, got %v", want, team.Required)
+	}
+}
+
+func TestMapSchema(t *testing.T) {
+	type Inventory struct {
+		Counts    map[string]int          `json:"counts"`
+		Locations map[string]*testAddress `json:"locations"`
+		ByYear    map[int]string          `json:"byYear"`
+	}
+
+	mux := http.NewServeMux()
+	r := New(mux, "Example API", "1.0.0")
+	r.UseOpenapiDocs(true)
+
+	r.Get("/inventory", func(w http.ResponseWriter, r *http.Request) {}).
+		Response(http.StatusOK, Inventory{})
+
+	props := r.OpenAPI().Components.Schemas["Inventory"].Properties
+	for name, want := range map[string]Schema{
+		"counts":    {Type: "integer"},
+		"locations": {Ref: "#/components/schemas/testAddress"},
+		"byYear":    {Type: "string"},
+	} {
+		s := props[name]
+		if s.Type != "object" || s.AdditionalProperties == nil || !reflect.DeepEqual(*s.AdditionalProperties, want) {
+			t.Errorf("Expected %s to be an object of %+v, got %+v", name, want, s)
+		}
+	}
+
+	b, err := json.Marshal(props["counts"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"type":"object","additionalProperties":{"type":"integer"}}`; string(b) != want {
+		t.Errorf("Expected %s, got %s", want, b)
+	}
+}
+
+func TestPrimitiveSliceSchema(t *testing.T) {
+	type Stats struct {
+		Counts []int           `json:"counts"`
+		Labels []string        `json:"labels"`
+		Matrix [][]int         `json:"matrix"`
+		Digest [4]byte         `json:"digest"`
+		Avatar []byte          `json:"avatar"`
+		Extra  json.RawMessage `json:"extra"`
+	}
+
+	mux := http.NewServeMux()
+	r := New(mux, "Example API", "1.0.0")
+	r.UseOpenapiDocs(true)
+
+	handler := func(w http.ResponseWriter, r *http.Request) {}
+	r.Get("/labels", handler).Response(http.StatusOK, []string{})
+	r.Get("/counts", handler).Response(http.StatusOK, []int{})
+	r.Get("/stats", handler).Response(http.StatusOK, Stats{})
+	r.Get("/all-stats", handler).Response(http.StatusOK, []Stats{})
+
+	doc := r.OpenAPI()
+	for path, want := range map[string]Schema{
+		"/labels":    {Type: "array", Items: &Schema{Type: "string"}},
+		"/counts":    {Type: "array", Items: &Schema{Type: "integer"}},
+		"/all-stats": {Type: "array", Items: &Schema{Ref: "#/components/schemas/Stats"}},
+	} {
+		got := doc.Paths[path].Get.Responses["200"].Content["application/json"].Schema
+		if got == nil || !reflect.DeepEqual(*got, want) {
+			t.Errorf("%s: Expected schema %+v, got %+v", path, want, got)
+		}
+	}
+
+	zero := 0.0
+	props := doc.Components.Schemas["Stats"].Properties
+	for name, want := range map[string]Schema{
+		"counts": {Type: "array", Items: &Schema{Type: "integer"}},
+		"labels": {Type: "array", Items: &Schema{Type: "string"}},
+		"matrix": {Type: "array", Items: &Schema{Type: "array", Items: &Schema{Type: "integer"}}},
+		"avatar": {Type: "string", Format: "byte"},
+		"digest": {Type: "array", Items: &Schema{Type: "integer", Format: "uint8", Minimum: &zero}},
+		"extra":  {},
+	} {
+		if got := props[name]; !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: Expected schema %+v, got %+v", name, want, got)
+		}
+	}
+	if _, ok := doc.Components.Schemas["string"]; ok {
+		t.Error("Expected no component schema for string")
+	}
+}
+
+func TestRequestBodyJSONTags(t *testing.T) {
+	type NewUser struct {
+		Name     string `json:"name"`
+		Email    string `json:"email,omitempty"`
+		Password string `json:"-"`
+		Age      int
+		internal string
+	}
+
+	mux := http.NewServeMux()
+	r := New(mux, "Example API", "1.0.0")
+	r.UseOpenapiDocs(true)
+
+	r.Post("/users", func(w http.ResponseWriter, r *http.Request) {}, Docs{
+		In: map[string]DocIn{
+			"application/json": {Object: NewUser{internal: "unused"}},
+		},
+	})
+
+	props := r.OpenAPI().Components.Schemas["NewUser"].Properties
+	for _, name := range []string{"name", "email", "Age"} {
+		if _, ok := props[name]; !ok {
+			t.Errorf("Expected property %q, got %v", name, props)
+		}
+	}
+	for _, name := range []string{"Name", "Password", "-", "internal"} {
+		if _, ok := props[name]; ok {
+			t.Errorf("Expected no property %q", name)
+		}
+	}
+}
+
+func TestRequestBodyTypes(t *testing.T) {
+	type NewOrder struct {
+		Quantity int64       `json:"quantity"`
+		Items    uint32      `json:"items"`
+		Gift     bool        `json:"gift"`
+		Price    float64     `json:"price"`
+		Note     string      `json:"note"`
+		Address  testAddress `json:"address"`
+	}
+
+	mux := http.NewServeMux()
+	r := New(mux, "Example API", "1.0.0")
+	r.UseOpenapiDocs(true)
+
+	r.Post("/orders", func(w http.ResponseWriter, r *http.Request) {}).Body(NewOrder{})
+
+	doc := r.OpenAPI()
+	body := doc.Paths["/orders"].Post.RequestBody.Content["application/json"].Schema
+	if body == nil || body.Ref != "#/components/schemas/NewOrder" {
+		t.Fatalf("Expected request body to reference NewOrder, got %+v", body)
+	}
+
+	zero := 0.0
+	props := doc.Components.Schemas["NewOrder"].Properties
+	for name, want := range map[string]Schema{
+		"quantity": {Type: "integer", Format: "int64"},
+		"items":    {Type: "integer", Format: "uint32", Minimum: &zero},
+		"gift":     {Type: "boolean"},
+		"price":    {Type: "number", Format: "double"},
+		"note":     {Type: "string"},
+		"address":  {Ref: "#/components/schemas/testAddress"},
+	} {
+		if got := props[name]; !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: Expected schema %+v, got %+v", name, want, got)
+		}
+	}
+	if _, ok := doc.Components.Schemas["testAddress"]; !ok {
+		t.Error("Expected the nested struct schema in the components")
+	}
+}
+
+func TestDescriptionTag(t *testing.T) {
+	type Person struct {
+		Name    string      `json:"name" description:"The user's full name"`
+		Email   string      `json:"email" description:"Contact address" openapi:"format=email"`
+		Address testAddress `json:"address" description:"Home address"`
+		Age     int         `json:"age"`
+	}
+
+	mux := http.NewServeMux()
+	r := New(mux, "Example API", "1.0.0")
+	r.UseOpenapiDocs(true)
+
+	r.Get("/people/{id}", func(w http.ResponseWriter, r *http.Request) {}).Response(http.StatusOK, Person{})
+
+	props := r.OpenAPI().Components.Schemas["Person"].Properties
+	for name, want := range map[string]Schema{
+		"name":    {Type: "string", Description: "The user's full name"},
+		"email":   {Type: "string", Format: "email", Description: "Contact address"},
+		"address": {AllOf: []Schema{{Ref: "#/components/schemas/testAddress"}}, Description: "Home address"},
+		"age":     {Type: "integer"},
+	} {
+		if got := props[name]; !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: Expected schema %+v, got %+v", name, want, got)
+		}
+	}
+}
+
+func TestComponentNames(t *testing.T) {
+	page := testPage[UserCreated]{}
+
+	// same name as the package level type
+	type UserCreated struct {
+		ID int `json:"id"`
+	}
+
+	mux := http.NewServeMux()
+	r := New(mux, "Example API", "1.0.0")
+	r.UseOpenapiDocs(true)
+
+	r.Get("/users", func(w http.ResponseWriter, r *http.Request) {}).Response(http.StatusOK, page)
+	r.Post("/users", func(w http.ResponseWriter, r *http.Request) {}).Response(http.StatusCreated, UserCreated{})
+
+	schemas := r.OpenAPI().Components.Schemas
+	pageSchema, ok := schemas["testPageUserCreated"]
+	if !ok {
+		t.Fatalf("Expected a testPageUserCreated component, got %v", slices.Sorted(maps.Keys(schemas)))
+	}
+	if items := pageSchema.Properties["items"]; items.Items == nil || items.Items.Ref != "#/components/schemas/UserCreated" {
+		t.Errorf("Expected the items to reference UserCreated, got %+v", items)
+	}
+
+	created := r.OpenAPI().Paths["/users"].Post.Responses["201"].Content["application/json"].Schema
+	if created.Ref != "#/components/schemas/gorouter.UserCreated" {
+		t.Errorf("Expected the colliding type to be prefixed with its package, got %s", created.Ref)
+	}
+	if schemas["UserCreated"].Properties["name"].Type != "string" || schemas["gorouter.UserCreated"].Properties["id"].Type != "integer" {
+		t.Errorf("Expected both UserCreated types to be documented, got %+v", schemas)
+	}
+
+	if err := r.Check(); err != nil {
+		t.Errorf("Expected valid component names, got %v", err)
+	}
+}
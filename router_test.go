@@ -1,9 +1,13 @@
 package router
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"reflect"
 	"testing"
 
 	"github.com/donseba/go-router/middleware"
@@ -206,3 +210,759 @@ func TestRouter(t *testing.T) {
 	// Run the tests
 	runTests("", tests)
 }
+
+func TestGroupRedirectTrailingSlash(t *testing.T) {
+	mux := http.NewServeMux()
+	r := New(mux, "Example API", "1.0.0")
+	r.RedirectTrailingSlash(false)
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, r.URL.Path)
+	}
+
+	r.Get("/about", handler)
+	r.Group("/api", func(api *Router) {
+		api.RedirectTrailingSlash(true)
+		api.Get("/users", handler)
+
+		api.Group("/strict", func(strict *Router) {
+			strict.RedirectTrailingSlash(false)
+			strict.Get("/items", handler)
+		})
+	})
+	r.Get("/apiv2/users", handler)
+
+	testCases := []struct {
+		path     string
+		status   int
+		location string
+	}{
+		{"/api/users/", DefaultRedirectStatusCode, "/api/users"},
+		{"/about/", http.StatusNotFound, ""},
+		{"/api/strict/items/", http.StatusNotFound, ""},
+		{"/apiv2/users/", http.StatusNotFound, ""},
+		{"/api/users", http.StatusOK, ""},
+	}
+
+	for _, tc := range testCases {
+		req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != tc.status {
+			t.Errorf("For path %s, expected status %d, got %d", tc.path, tc.status, w.Code)
+		}
+		if w.Header().Get("Location") != tc.location {
+			t.Errorf("For path %s, expected Location %q, got %q", tc.path, tc.location, w.Header().Get("Location"))
+		}
+	}
+
+	// requests without a path, such as CONNECT, must not be mistaken for one
+	// with a trailing slash
+	req := httptest.NewRequest(http.MethodConnect, "/", nil)
+	req.URL.Path = ""
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Header().Get("Location") != "" {
+		t.Errorf("Expected no redirect without a path, got Location %q", w.Header().Get("Location"))
+	}
+}
+
+func TestOptionsAsterisk(t *testing.T) {
+	mux := http.NewServeMux()
+	r := New(mux, "Example API", "1.0.0")
+
+	handler := func(w http.ResponseWriter, r *http.Request) {}
+	r.Get("/users", handler)
+	r.Post("/users", handler)
+	r.Group("/api", func(api *Router) {
+		api.Delete("/users/{id}", handler)
+	})
+
+	req := httptest.NewRequest(http.MethodOptions, "*", nil)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Header().Get("Allow") != "" {
+		t.Errorf("Expected no Allow header when disabled, got %q", w.Header().Get("Allow"))
+	}
+
+	r.HandleOptionsAsterisk(true)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNoContent {
+		t.Errorf("Expected status %d, got %d", http.StatusNoContent, w.Code)
+	}
+	if allow := w.Header().Get("Allow"); allow != "OPTIONS, GET, HEAD, POST, DELETE" {
+		t.Errorf("Expected Allow %q, got %q", "OPTIONS, GET, HEAD, POST, DELETE", allow)
+	}
+}
+
+func TestOptionsStatus(t *testing.T) {
+	mux := http.NewServeMux()
+	r := New(mux, "Example API", "1.0.0")
+	r.UseOpenapiDocs(true)
+	r.OptionsStatus(http.StatusOK)
+	r.OptionsBody(true)
+
+	r.Get("/users", func(w http.ResponseWriter, r *http.Request) {}, Docs{Summary: "List users"})
+
+	req := httptest.NewRequest(http.MethodOptions, "/users", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if allow := w.Header().Get("Allow"); allow != "OPTIONS, GET" {
+		t.Errorf("Expected Allow %q, got %q", "OPTIONS, GET", allow)
+	}
+	if body := w.Body.String(); body != `{"allow":["OPTIONS","GET"]}`+"\n" {
+		t.Errorf("Unexpected body %q", body)
+	}
+}
+
+func TestUseContext(t *testing.T) {
+	type ctxKey string
+
+	mux := http.NewServeMux()
+	r := New(mux, "Example API", "1.0.0")
+
+	r.UseContext(func(ctx context.Context, req *http.Request) context.Context {
+		return context.WithValue(ctx, ctxKey("greeting"), "hello")
+	})
+	r.UseContext(func(ctx context.Context, req *http.Request) context.Context {
+		return context.WithValue(ctx, ctxKey("greeting"), ctx.Value(ctxKey("greeting")).(string)+" world")
+	})
+
+	r.Get("/greet", func(w http.ResponseWriter, req *http.Request) {
+		fmt.Fprint(w, req.Context().Value(ctxKey("greeting")))
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/greet", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if body := w.Body.String(); body != "hello world" {
+		t.Errorf("Expected body %q, got %q", "hello world", body)
+	}
+}
+
+func TestNilHandler(t *testing.T) {
+	mux := http.NewServeMux()
+	r := New(mux, "Example API", "1.0.0")
+
+	defer func() {
+		rec := recover()
+		if rec == nil {
+			t.Fatal("Expected registering a nil handler to panic")
+		}
+		if msg := fmt.Sprint(rec); msg != "router: nil handler for GET /users" {
+			t.Errorf("Unexpected panic message %q", msg)
+		}
+	}()
+
+	r.Get("/users", nil)
+}
+
+// exactMux is a minimal Mux matching method and path exactly.
+type exactMux map[string]http.Handler
+
+func (m exactMux) Handle(pattern string, handler http.Handler) {
+	m[pattern] = handler
+}
+
+func (m exactMux) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if handler, ok := m[req.Method+" "+req.URL.Path]; ok {
+		handler.ServeHTTP(w, req)
+		return
+	}
+	http.NotFound(w, req)
+}
+
+func TestCustomMux(t *testing.T) {
+	mux := exactMux{}
+	r := New(mux, "Example API", "1.0.0")
+	r.UseOpenapiDocs(true)
+
+	r.Group("/api", func(api *Router) {
+		api.Get("/users", func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, "users")
+		}, Docs{Summary: "List users"})
+	})
+
+	if _, ok := mux["GET /api/users"]; !ok {
+		t.Fatalf("Expected the route to be registered on the custom mux, got %v", mux)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/users", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK || w.Body.String() != "users" {
+		t.Errorf("Expected 200 users, got %d %q", w.Code, w.Body.String())
+	}
+
+	if r.OpenAPI().Paths["/api/users"].Get == nil {
+		t.Error("Expected the route to be documented")
+	}
+
+	t.Run("Status handlers run the middlewares once", func(t *testing.T) {
+		mux := exactMux{}
+		r := New(mux, "Example API", "1.0.0")
+
+		calls := 0
+		r.Use(func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				calls++
+				next.ServeHTTP(w, req)
+			})
+		})
+		r.HandleStatus(http.StatusNotFound, func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "custom not found", http.StatusNotFound)
+		})
+		r.Get("/users/1", func(w http.ResponseWriter, r *http.Request) {
+			http.NotFound(w, r)
+		})
+
+		for _, path := range []string{"/users/1", "/missing"} {
+			calls = 0
+			req := httptest.NewRequest(http.MethodGet, path, nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != http.StatusNotFound || w.Body.String() != "custom not found\n" {
+				t.Errorf("For path %s, expected custom 404 response, got %d %q", path, w.Code, w.Body.String())
+			}
+			if calls != 1 {
+				t.Errorf("For path %s, expected the middleware to run once, got %d", path, calls)
+			}
+		}
+	})
+}
+
+func TestAutoHead(t *testing.T) {
+	mux := http.NewServeMux()
+	r := New(mux, "Example API", "1.0.0")
+	r.UseOpenapiDocs(true)
+	r.AutoHead(true)
+	r.HandleStatus(http.StatusMethodNotAllowed, func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	})
+
+	r.Get("/users", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Users", "2")
+		fmt.Fprint(w, "alice,bob")
+	}, Docs{Summary: "List users"})
+	r.Group("/plain", func(plain *Router) {
+		plain.AutoHead(false)
+		plain.Get("/users", func(w http.ResponseWriter, r *http.Request) {}, Docs{Summary: "List plain users"})
+	})
+
+	t.Run("HEAD discards the body", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodHead, "/users", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("Expected status %d, got %d", http.StatusOK, w.Code)
+		}
+		if w.Body.Len() != 0 {
+			t.Errorf("Expected no body, got %q", w.Body.String())
+		}
+		if cl := w.Header().Get("Content-Length"); cl != "9" {
+			t.Errorf("Expected Content-Length 9, got %q", cl)
+		}
+		if w.Header().Get("X-Users") != "2" {
+			t.Error("Expected the GET handler's headers")
+		}
+	})
+
+	t.Run("HEAD is not documented", func(t *testing.T) {
+		if methods := r.OpenAPI().Paths["/users"].Methods(); len(methods) != 1 || methods[0] != http.MethodGet {
+			t.Errorf("Expected only GET to be documented, got %v", methods)
+		}
+	})
+
+	t.Run("Allow lists HEAD", func(t *testing.T) {
+		for _, method := range []string{http.MethodPost, http.MethodOptions} {
+			req := httptest.NewRequest(method, "/users", nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if allow := w.Header().Get("Allow"); allow != "OPTIONS, GET, HEAD" {
+				t.Errorf("%s: Expected Allow %q, got %q", method, "OPTIONS, GET, HEAD", allow)
+			}
+		}
+	})
+
+	t.Run("Allow omits HEAD without AutoHead", func(t *testing.T) {
+		for _, method := range []string{http.MethodPost, http.MethodOptions} {
+			req := httptest.NewRequest(method, "/plain/users", nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if allow := w.Header().Get("Allow"); allow != "OPTIONS, GET" {
+				t.Errorf("%s: Expected Allow %q, got %q", method, "OPTIONS, GET", allow)
+			}
+		}
+	})
+}
+
+func TestNewDefault(t *testing.T) {
+	r := NewDefault()
+	r.UseOpenapiDocs(true)
+
+	r.Get("/ping", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "pong")
+	}, Docs{Summary: "Ping"})
+
+	req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Body.String() != "pong" {
+		t.Errorf("Expected body %q, got %q", "pong", w.Body.String())
+	}
+
+	doc := r.OpenAPI()
+	if doc.Info.Title != "API" || doc.Info.Version != "0.0.0" {
+		t.Errorf("Expected default info, got %+v", doc.Info)
+	}
+	if doc.Paths["/ping"].Get == nil {
+		t.Error("Expected GET /ping to be documented")
+	}
+}
+
+func TestConditionalRoutes(t *testing.T) {
+	mux := http.NewServeMux()
+	r := New(mux, "Example API", "1.0.0")
+	r.UseOpenapiDocs(true)
+
+	handler := func(w http.ResponseWriter, r *http.Request) {}
+	r.GetIf(true, "/enabled", handler, Docs{Summary: "Enabled"})
+	r.GetIf(false, "/disabled", handler, Docs{Summary: "Disabled"}).Tag("beta")
+
+	tests := []struct {
+		path     string
+		expected int
+	}{
+		{"/enabled", http.StatusOK},
+		{"/disabled", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != tt.expected {
+			t.Errorf("%s: Expected status %d, got %d", tt.path, tt.expected, w.Code)
+		}
+	}
+
+	paths := r.OpenAPI().Paths
+	if _, ok := paths["/enabled"]; !ok {
+		t.Error("Expected /enabled in the spec")
+	}
+	if _, ok := paths["/disabled"]; ok {
+		t.Error("Expected /disabled to be absent from the spec")
+	}
+}
+
+func TestGetDocs(t *testing.T) {
+	mux := http.NewServeMux()
+	r := New(mux, "Example API", "1.0.0")
+
+	handler := func(w http.ResponseWriter, r *http.Request) {}
+	r.Group("/users", func(users *Router) {
+		users.Get("/{id}", handler, Docs{
+			Summary:     "Get user",
+			Description: "Returns a single user.",
+			Parameters:  []Parameter{{Name: "id", In: "path", Required: true}},
+		})
+		users.Delete("/{id}", handler).Summary("Delete user")
+	})
+	r.Get("/health", handler)
+
+	docs := r.GetDocs()
+	if len(docs) != 3 {
+		t.Fatalf("Expected 3 routes, got %d: %+v", len(docs), docs)
+	}
+
+	if d := docs[0]; d.Method != http.MethodGet || d.Pattern != "/users/{id}" || d.Title != "Get user" ||
+		d.Description != "Returns a single user." || len(d.Params) != 1 || d.Params[0].Name != "id" {
+		t.Errorf("Unexpected doc %+v", d)
+	}
+	if d := docs[1]; d.Method != http.MethodDelete || d.Title != "Delete user" {
+		t.Errorf("Expected the builder's summary, got %+v", d)
+	}
+	if d := docs[2]; d.Pattern != "/health" || d.Title != "" {
+		t.Errorf("Unexpected undocumented route %+v", d)
+	}
+}
+
+func TestServeHTTPNoOutput(t *testing.T) {
+	mux := http.NewServeMux()
+	r := New(mux, "Example API", "1.0.0")
+	r.UseOpenapiDocs(true)
+
+	r.Get("/users", func(w http.ResponseWriter, r *http.Request) {}, Docs{Summary: "List users"})
+
+	stdout := os.Stdout
+	pr, pw, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Stdout = pw
+	defer func() { os.Stdout = stdout }()
+
+	req := httptest.NewRequest(http.MethodGet, "/users", nil)
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	os.Stdout = stdout
+	_ = pw.Close()
+
+	out, err := io.ReadAll(pr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out) != 0 {
+		t.Errorf("Expected no output on stdout, got %q", out)
+	}
+}
+
+func TestInterceptedWrite(t *testing.T) {
+	mux := http.NewServeMux()
+	r := New(mux, "Example API", "1.0.0")
+	r.HandleStatus(http.StatusNotFound, func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, "custom not found")
+	})
+
+	var (
+		n   int
+		err error
+	)
+	r.Get("/users/{id}", func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		n, err = w.Write([]byte("user not found"))
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/users/42", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if err != nil || n != len("user not found") {
+		t.Errorf("Expected the handler's write to report %d bytes, got %d, %v", len("user not found"), n, err)
+	}
+	if w.Code != http.StatusNotFound || w.Body.String() != "custom not found" {
+		t.Errorf("Expected only the custom handler's response, got %d %q", w.Code, w.Body.String())
+	}
+}
+
+func TestRoutes(t *testing.T) {
+	mux := http.NewServeMux()
+	r := New(mux, "Example API", "1.0.0")
+
+	handler := func(w http.ResponseWriter, r *http.Request) {}
+	r.Get("/health", handler)
+	r.Group("/users", func(users *Router) {
+		users.Get("/{id}", handler, Docs{Summary: "Get user", Tags: []string{"users"}})
+		users.Delete("/{id}", handler).Summary("Delete user").Tag("users", "admin")
+	})
+
+	expected := []RouteInfo{
+		{Method: http.MethodGet, Pattern: "/health"},
+		{Method: http.MethodGet, Pattern: "/users/{id}", Group: true, Tags: []string{"users"}, Summary: "Get user"},
+		{Method: http.MethodDelete, Pattern: "/users/{id}", Group: true, Tags: []string{"users", "admin"}, Summary: "Delete user"},
+	}
+	if routes := r.Routes(); !reflect.DeepEqual(routes, expected) {
+		t.Errorf("Expected routes %+v, got %+v", expected, routes)
+	}
+}
+
+func TestURLFor(t *testing.T) {
+	mux := http.NewServeMux()
+	r := New(mux, "Example API", "1.0.0")
+
+	handler := func(w http.ResponseWriter, r *http.Request) {}
+	r.Group("/users", func(users *Router) {
+		users.Get("/{id}", handler, Docs{Name: "user.show"})
+		users.Get("/{id}/files/{path...}", handler, Docs{Name: "user.file"})
+	})
+	r.Get("/{$}", handler, Docs{Name: "home"})
+
+	tests := []struct {
+		name     string
+		pairs    []string
+		expected string
+		err      string
+	}{
+		{"user.show", []string{"id", "123"}, "/users/123", ""},
+		{"user.show", []string{"id", "a b"}, "/users/a%20b", ""},
+		{"user.file", []string{"id", "1", "path", "docs/cv.pdf"}, "/users/1/files/docs/cv.pdf", ""},
+		{"home", nil, "/", ""},
+		{"user.show", nil, "", `router: URLFor "user.show": missing value for {id}`},
+		{"user.unknown", nil, "", `router: URLFor: unknown route name "user.unknown"`},
+	}
+
+	for _, tt := range tests {
+		got, err := r.URLFor(tt.name, tt.pairs...)
+		if tt.err != "" {
+			if err == nil || err.Error() != tt.err {
+				t.Errorf("%s: Expected error %q, got %v", tt.name, tt.err, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.expected {
+			t.Errorf("%s: Expected %q, got %q, %v", tt.name, tt.expected, got, err)
+		}
+	}
+}
+
+func TestHandlerRegistration(t *testing.T) {
+	mux := http.NewServeMux()
+	r := New(mux, "Example API", "1.0.0")
+
+	var calls []string
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			calls = append(calls, "middleware")
+			next.ServeHTTP(w, req)
+		})
+	})
+
+	r.GetHandler("/files/", http.StripPrefix("/files", http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		calls = append(calls, "handler "+req.URL.Path)
+		w.WriteHeader(http.StatusOK)
+	})))
+	r.PostHandler("/redirect", http.RedirectHandler("/files/", http.StatusSeeOther))
+
+	req := httptest.NewRequest(http.MethodGet, "/files/a.txt", nil)
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", rr.Code)
+	}
+	if want := []string{"middleware", "handler /a.txt"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("Expected calls %v, got %v", want, calls)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/redirect", nil)
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusSeeOther || rr.Header().Get("Location") != "/files/" {
+		t.Errorf("Expected a redirect to /files/, got %d %q", rr.Code, rr.Header().Get("Location"))
+	}
+
+	defer func() {
+		if msg := fmt.Sprint(recover()); msg != "router: nil handler for PUT /users" {
+			t.Errorf("Unexpected panic message %q", msg)
+		}
+	}()
+	r.PutHandler("/users", nil)
+}
+
+func TestHandleMethodNotAllowed(t *testing.T) {
+	mux := http.NewServeMux()
+	r := New(mux, "Example API", "1.0.0")
+
+	handler := func(w http.ResponseWriter, r *http.Request) {}
+	r.HandleStatus(http.StatusMethodNotAllowed, func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		_, _ = w.Write([]byte("<h1>Method not allowed</h1>"))
+	})
+
+	r.Group("/api", func(api *Router) {
+		api.Get("/users/{id}", handler)
+		api.Post("/users/{id}", handler)
+		api.HandleMethodNotAllowed("/users/{id}", func(w http.ResponseWriter, req *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			_, _ = fmt.Fprintf(w, `{"allowed":%q}`, w.Header().Get("Allow"))
+		})
+	})
+	r.Get("/about", handler)
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/users/1", nil)
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected status 405, got %d", rr.Code)
+	}
+	if got := rr.Header().Get("Allow"); got != "GET, HEAD, POST" {
+		t.Errorf("Expected Allow %q, got %q", "GET, HEAD, POST", got)
+	}
+	if got := rr.Body.String(); got != `{"allowed":"GET, HEAD, POST"}` {
+		t.Errorf("Expected the route specific body, got %q", got)
+	}
+	if rr.Header().Get(HeaderFlagDoNotIntercept) != "" {
+		t.Error("Expected the do not intercept flag to be stripped")
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/users/1", nil)
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Errorf("Expected allowed methods to be served, got %d", rr.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodPut, "/about", nil)
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	if rr.Code != http.StatusMethodNotAllowed || rr.Body.String() != "<h1>Method not allowed</h1>" {
+		t.Errorf("Expected the global 405 handler elsewhere, got %d %q", rr.Code, rr.Body.String())
+	}
+
+	merged := New(http.NewServeMux(), "Example API", "1.0.0")
+	merged.Merge("/v1", r)
+
+	req = httptest.NewRequest(http.MethodDelete, "/v1/api/users/1", nil)
+	rr = httptest.NewRecorder()
+	merged.ServeHTTP(rr, req)
+	if rr.Code != http.StatusMethodNotAllowed || rr.Body.String() != `{"allowed":"GET, HEAD, POST"}` {
+		t.Errorf("Expected the route specific handler to be merged, got %d %q", rr.Code, rr.Body.String())
+	}
+	if allowed := merged.AllowedMethods(httptest.NewRequest(http.MethodGet, "/v1/api/users/1", nil)); !reflect.DeepEqual(allowed, []string{"GET", "HEAD", "POST"}) {
+		t.Errorf("Expected the merged handler not to count as a route, got %v", allowed)
+	}
+}
+
+func TestHostGroup(t *testing.T) {
+	mux := http.NewServeMux()
+	r := New(mux, "Example API", "1.0.0")
+	r.UseOpenapiDocs(true)
+
+	reply := func(body string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(body))
+		}
+	}
+
+	api := r.Host("api.example.com")
+	api.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Host-Router", "api")
+			next.ServeHTTP(w, r)
+		})
+	})
+	api.Group("/v1", func(v1 *Router) {
+		v1.Get("/users", reply("api v1 users"), Docs{Summary: "List users"})
+	})
+	r.Get("/v1/status", reply("status"))
+
+	tests := []struct {
+		host     string
+		path     string
+		status   int
+		body     string
+		hostFlag string
+	}{
+		{"api.example.com", "/v1/users", http.StatusOK, "api v1 users", "api"},
+		{"www.example.com", "/v1/users", http.StatusNotFound, "", ""},
+		{"api.example.com", "/v1/status", http.StatusOK, "status", ""},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		req.Host = tt.host
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+
+		if rr.Code != tt.status {
+			t.Errorf("%s%s: Expected status %d, got %d", tt.host, tt.path, tt.status, rr.Code)
+			continue
+		}
+		if tt.body != "" && rr.Body.String() != tt.body {
+			t.Errorf("%s%s: Expected body %q, got %q", tt.host, tt.path, tt.body, rr.Body.String())
+		}
+		if got := rr.Header().Get("X-Host-Router"); got != tt.hostFlag {
+			t.Errorf("%s%s: Expected X-Host-Router %q, got %q", tt.host, tt.path, tt.hostFlag, got)
+		}
+	}
+
+	op := r.OpenAPI().Paths["/v1/users"].Get
+	if op == nil {
+		t.Fatal("Expected /v1/users to be documented")
+	}
+	if want := []Server{{URL: "https://api.example.com"}}; !reflect.DeepEqual(op.Servers, want) {
+		t.Errorf("Expected servers %v, got %v", want, op.Servers)
+	}
+
+	routes := r.Routes()
+	if routes[0].Host != "api.example.com" || routes[0].Pattern != "/v1/users" {
+		t.Errorf("Expected the host route first, got %+v", routes[0])
+	}
+}
+
+func TestHostSharedPath(t *testing.T) {
+	mux := http.NewServeMux()
+	r := New(mux, "Example API", "1.0.0")
+	r.UseOpenapiDocs(true)
+
+	summary := func(w http.ResponseWriter, r *http.Request) {
+		docs, _ := RouteDocsFrom(r.Context())
+		_, _ = w.Write([]byte(docs.Summary))
+	}
+
+	api := r.Host("api.example.com")
+	r.Get("/items", summary, Docs{Summary: "List items"})
+	api.Post("/items", summary, Docs{Summary: "Create item"})
+	r.Get("/items/{id}", summary, Docs{Summary: "Get item", Deprecated: true})
+	api.Get("/items/{id}", summary, Docs{Summary: "Get api item"})
+
+	options := []struct {
+		host  string
+		path  string
+		allow string
+	}{
+		{"www.example.com", "/items", "OPTIONS, GET"},
+		{"api.example.com", "/items", "OPTIONS, POST"},
+		{"www.example.com", "/items/1", "OPTIONS, GET"},
+		{"api.example.com", "/items/1", "OPTIONS, GET"},
+	}
+
+	for _, tt := range options {
+		req := httptest.NewRequest(http.MethodOptions, tt.path, nil)
+		req.Host = tt.host
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusNoContent {
+			t.Errorf("OPTIONS %s%s: Expected status %d, got %d", tt.host, tt.path, http.StatusNoContent, rr.Code)
+		}
+		if got := rr.Header().Get("Allow"); got != tt.allow {
+			t.Errorf("OPTIONS %s%s: Expected Allow %q, got %q", tt.host, tt.path, tt.allow, got)
+		}
+	}
+
+	gets := []struct {
+		host       string
+		body       string
+		deprecated string
+	}{
+		{"www.example.com", "Get item", "true"},
+		{"api.example.com", "Get api item", ""},
+	}
+
+	for _, tt := range gets {
+		req := httptest.NewRequest(http.MethodGet, "/items/1", nil)
+		req.Host = tt.host
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+
+		if rr.Body.String() != tt.body {
+			t.Errorf("GET %s/items/1: Expected route docs %q, got %q", tt.host, tt.body, rr.Body.String())
+		}
+		if got := rr.Header().Get("Deprecation"); got != tt.deprecated {
+			t.Errorf("GET %s/items/1: Expected Deprecation %q, got %q", tt.host, tt.deprecated, got)
+		}
+	}
+}
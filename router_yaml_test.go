@@ -0,0 +1,94 @@
+package router
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"testing"
+)
+
+func TestOpenAPIYAML(t *testing.T) {
+	type Address struct {
+		City string `json:"city"`
+	}
+	type User struct {
+		Name    string  `json:"name"`
+		Tags    []int   `json:"tags"`
+		Address Address `json:"address"`
+		Yes     bool    `json:"yes"`
+		Dash    string  `json:"-,"`
+		Colon   string  `json:"a:b"`
+	}
+
+	mux := http.NewServeMux()
+	r := New(mux, "Example API", "1.0.0")
+	r.UseOpenapiDocs(true)
+
+	r.Put("/users/{id}", func(w http.ResponseWriter, r *http.Request) {}).
+		Summary("Update user: \"quoted\" # not a comment").
+		Description("Multi\nline").
+		Tag("users", "-admin").
+		Param(Parameter{Name: "id", In: "path", Required: true, Schema: &Schema{Type: "string"}}).
+		Param(Parameter{Name: "confirm", In: "query", Schema: &Schema{Type: "string", Enum: []any{"yes", "no", "", "- item", "key: value", "# hash"}}}).
+		Body(User{}).
+		Response(http.StatusOK, User{}).
+		Response(http.StatusNoContent, nil)
+
+	got, err := r.OpenAPIYAML()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want, err := os.ReadFile("testdata/openapi.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(got) != string(want) {
+		t.Errorf("Expected YAML\n%s\ngot\n%s", want, got)
+	}
+}
+
+func TestYAMLScalars(t *testing.T) {
+	tests := []struct {
+		value any
+		want  string
+	}{
+		{"", `""`},
+		{"a: b", `"a: b"`},
+		{"# comment", `"# comment"`},
+		{"- item", `"- item"`},
+		{"yes", `"yes"`},
+		{"no", `"no"`},
+		{"multi\nline", `"multi\nline"`},
+		{"<html> & 'quotes'", `"<html> & 'quotes'"`},
+		{json.Number("1.50"), "1.50"},
+		{true, "true"},
+		{nil, "null"},
+	}
+
+	for _, tt := range tests {
+		if got := yamlScalar(tt.value); got != tt.want {
+			t.Errorf("Expected scalar %#v to be written %s, got %s", tt.value, tt.want, got)
+		}
+	}
+
+	keys := map[string]string{
+		"name":    "name",
+		"x-logo":  "x-logo",
+		"$ref":    "$ref",
+		"200":     `"200"`,
+		"yes":     `"yes"`,
+		"No":      `"No"`,
+		"-":       `"-"`,
+		"a:b":     `"a:b"`,
+		"/users":  `"/users"`,
+		"":        `""`,
+		"#hashed": `"#hashed"`,
+	}
+	for key, want := range keys {
+		if got := yamlKey(key); got != want {
+			t.Errorf("Expected key %q to be written %s, got %s", key, want, got)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package router
+
+import (
+	"net/http"
+	"strings"
+)
+
+// routeTableEntry is a single route as listed by HandleRouteTable.
+type routeTableEntry struct {
+	Method  string   `json:"method"`
+	Pattern string   `json:"pattern"`
+	Tags    []string `json:"tags"`
+	HasDocs bool     `json:"hasDocs"`
+}
+
+// HandleRouteTable registers a GET route listing every registered route as
+// JSON, in registration order, for tooling that does not parse OpenAPI. Each
+// entry holds the method, pattern, tags and whether the route is documented.
+// The given middlewares only wrap this route.
+func (r *Router) HandleRouteTable(pattern string, middlewares ...Middleware) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		_ = JSON(w, req, http.StatusOK, r.routeTable())
+	})
+
+	r.serveDocs(pattern, handler, middlewares)
+}
+
+func (r *Router) routeTable() []routeTableEntry {
+	rootRouter := r.rootParent()
+	rootRouter.mu.RLock()
+	defer rootRouter.mu.RUnlock()
+
+	entries := make([]routeTableEntry, 0, len(rootRouter.routes))
+	for _, rt := range rootRouter.routes {
+		entry := routeTableEntry{
+			Method:  rt.method,
+			Pattern: rt.pattern,
+			Tags:    []string{},
+		}
+
+		item := rootRouter.openapi.Paths[strings.ReplaceAll(rt.pattern, "{$}", "")]
+		if op := item.GetMethod(rt.method); op != nil {
+			entry.HasDocs = true
+			if op.Tags != nil {
+				entry.Tags = op.Tags
+			}
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries
+}
@@ -0,0 +1,409 @@
+package router
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"log"
+	"path"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	rawMessageType    = reflect.TypeOf(json.RawMessage(nil))
+)
+
+// timeSchema is the schema of time.Time, encoded as an RFC 3339 string.
+func timeSchema() Schema {
+	return Schema{Type: "string", Format: "date-time"}
+}
+
+// RegisterInterfaceImpl registers the concrete types implementing an interface,
+// so struct fields of that interface type are documented as a oneOf of the
+// implementations. Unregistered interface fields are documented with an empty,
+// permissive schema. The interface type is typically obtained with
+// reflect.TypeOf((*Payload)(nil)).Elem().
+func (r *Router) RegisterInterfaceImpl(iface reflect.Type, impls ...reflect.Type) {
+	rootRouter := r.rootParent()
+	rootRouter.mu.Lock()
+	defer rootRouter.mu.Unlock()
+
+	if rootRouter.interfaceImpls == nil {
+		rootRouter.interfaceImpls = make(map[reflect.Type][]reflect.Type)
+	}
+	rootRouter.interfaceImpls[iface] = append(rootRouter.interfaceImpls[iface], impls...)
+}
+
+// RegisterTypeSchema registers the schema documented for fields of the given
+// type, taking precedence over the reflection based schema. It makes library
+// types such as UUIDs or decimals document as intended, e.g.
+//
+//	r.RegisterTypeSchema(reflect.TypeOf(uuid.UUID{}), Schema{Type: "string", Format: "uuid"})
+//
+// Pointers to the type use the same schema.
+func (r *Router) RegisterTypeSchema(t reflect.Type, schema Schema) {
+	rootRouter := r.rootParent()
+	rootRouter.mu.Lock()
+	defer rootRouter.mu.Unlock()
+
+	if rootRouter.typeSchemas == nil {
+		rootRouter.typeSchemas = make(map[reflect.Type]Schema)
+	}
+	rootRouter.typeSchemas[t] = schema
+}
+
+// structSchema returns the object schema of a struct type. Schemas of the
+// types it references are added to components.
+func (r *Router) structSchema(t reflect.Type, components map[string]Schema) Schema {
+	var (
+		properties = make(map[string]Schema)
+		required   []string
+	)
+
+	for i := 0; i < t.NumField(); i++ {
+		fieldType := t.Field(i)
+		fieldName, ok := jsonFieldName(fieldType)
+		if !ok {
+			continue
+		}
+
+		schema := fieldTagSchema(fieldType, r.fieldSchema(fieldType.Type, components))
+		nullable := fieldType.Type.Kind() == reflect.Ptr
+		if schema.Ref != "" && (nullable || schema.Description != "") {
+			// siblings of $ref are ignored in OpenAPI 3.0
+			schema.AllOf = []Schema{{Ref: schema.Ref}}
+			schema.Ref = ""
+		}
+		schema.Nullable = nullable
+		properties[fieldName] = schema
+		if fieldRequired(fieldType) {
+			required = append(required, fieldName)
+		}
+	}
+
+	return Schema{
+		Type:       "object",
+		Properties: properties,
+		Required:   required,
+	}
+}
+
+// jsonFieldName returns the property name of a struct field as encoded by
+// encoding/json, and false for unexported fields and fields tagged `json:"-"`.
+func jsonFieldName(field reflect.StructField) (string, bool) {
+	if !field.IsExported() {
+		return "", false
+	}
+
+	jsonTag := field.Tag.Get("json")
+	if jsonTag == "-" {
+		return "", false
+	}
+	if name := strings.Split(jsonTag, ",")[0]; name != "" {
+		return name, true
+	}
+	return field.Name, true
+}
+
+// fieldRequired reports whether a struct field is a required property: when
+// tagged `openapi:"required"`, or `validate:"required"` unless its json tag
+// has omitempty. Pointer fields are nullable and never required.
+func fieldRequired(field reflect.StructField) bool {
+	if field.Type.Kind() == reflect.Ptr {
+		return false
+	}
+	if _, ok := openapiTagOption(field, "required"); ok {
+		return true
+	}
+	if slices.Contains(strings.Split(field.Tag.Get("json"), ",")[1:], "omitempty") {
+		return false
+	}
+	return slices.Contains(strings.Split(field.Tag.Get("validate"), ","), "required")
+}
+
+// fieldTagSchema applies the `description` struct tag and the options of the
+// `openapi` struct tag of a field to its schema, e.g. `openapi:"format=uuid"`.
+// The format and enum of slices and arrays apply to their items. References only
+// get the description. It panics on enum values not of the schema type.
+func fieldTagSchema(field reflect.StructField, schema Schema) Schema {
+	if description := field.Tag.Get("description"); description != "" {
+		schema.Description = description
+	}
+
+	format, hasFormat := openapiTagOption(field, "format")
+	enum, hasEnum := openapiTagOption(field, "enum")
+	if !hasFormat && !hasEnum {
+		return schema
+	}
+
+	target := &schema
+	for target.Type == "array" && target.Items != nil {
+		items := *target.Items // the items may be shared by a registered type schema
+		target.Items = &items
+		target = target.Items
+	}
+	if target.Ref != "" {
+		return schema
+	}
+
+	if hasFormat {
+		target.Format = format
+	}
+	if hasEnum {
+		values, err := enumValues(target.Type, strings.Split(enum, "|"))
+		if err != nil {
+			panic(fmt.Sprintf("router: enum of field %s: %v", field.Name, err))
+		}
+		target.Enum = values
+	}
+	return schema
+}
+
+// enumValues converts the enum values of an `openapi:"enum=a|b"` tag to the
+// schema type. It fails on values that do not parse as the type.
+func enumValues(typ string, values []string) ([]any, error) {
+	enum := make([]any, len(values))
+	for i, value := range values {
+		var (
+			v   any
+			err error
+		)
+		switch typ {
+		case "integer":
+			v, err = strconv.ParseInt(value, 10, 64)
+		case "number":
+			v, err = strconv.ParseFloat(value, 64)
+		case "boolean":
+			v, err = strconv.ParseBool(value)
+		default:
+			v = value
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a valid %s", value, typ)
+		}
+		enum[i] = v
+	}
+	return enum, nil
+}
+
+// openapiTagOption returns the value of an option of the `openapi` struct tag,
+// a comma separated list of name or name=value options, e.g.
+// `openapi:"required,format=uuid"`. It reports whether the option is present.
+func openapiTagOption(field reflect.StructField, name string) (string, bool) {
+	for _, option := range strings.Split(field.Tag.Get("openapi"), ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(option), "=")
+		if key == name {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// fieldSchema returns the schema of a struct field type. Named structs are
+// added to components and referenced, slices and arrays describe their items.
+// Byte slices are base64 strings, like encoding/json encodes them.
+func (r *Router) fieldSchema(t reflect.Type, components map[string]Schema) Schema {
+	typeSchemas := r.rootParent().typeSchemas
+	if schema, ok := typeSchemas[t]; ok {
+		return schema
+	}
+	if t.Kind() == reflect.Ptr {
+		if schema, ok := typeSchemas[t.Elem()]; ok {
+			return schema
+		}
+	}
+
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == timeType {
+		return timeSchema()
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		if t.Name() == "" {
+			return r.structSchema(t, components)
+		}
+		return r.componentRef(t, components)
+	case reflect.Slice, reflect.Array:
+		if t == rawMessageType {
+			return Schema{} // any JSON value
+		}
+		if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+			return Schema{Type: "string", Format: "byte"} // encoded as base64 by encoding/json
+		}
+		items := r.fieldSchema(t.Elem(), components)
+		return Schema{Type: "array", Items: &items}
+	case reflect.Map:
+		if !jsonMapKey(t.Key()) {
+			log.Printf("[go-router] map key type %s does not encode as a JSON object key, documenting %s by its values only", t.Key(), t)
+		}
+		values := r.fieldSchema(t.Elem(), components)
+		return Schema{Type: "object", AdditionalProperties: &values}
+	case reflect.Interface:
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		minimum := 0.0
+		return Schema{Type: kindType(t.Kind()), Format: kindFormat(t.Kind()), Minimum: &minimum}
+	default:
+		return Schema{Type: kindType(t.Kind()), Format: kindFormat(t.Kind())}
+	}
+
+	impls := r.rootParent().interfaceImpls[t]
+	if len(impls) == 0 {
+		return Schema{}
+	}
+
+	var schema Schema
+	for _, impl := range impls {
+		for impl.Kind() == reflect.Ptr {
+			impl = impl.Elem()
+		}
+
+		if impl.Kind() != reflect.Struct || impl.Name() == "" {
+			schema.OneOf = append(schema.OneOf, Schema{Type: kindType(impl.Kind())})
+			continue
+		}
+
+		schema.OneOf = append(schema.OneOf, r.componentRef(impl, components))
+	}
+
+	return schema
+}
+
+// componentRef adds the schema of a named struct type to components, unless
+// already present, and returns a reference to it. Types are added before their
+// fields are visited, so self-referential types end in a reference.
+func (r *Router) componentRef(t reflect.Type, components map[string]Schema) Schema {
+	name := r.componentName(t)
+	if _, ok := components[name]; !ok {
+		components[name] = Schema{} // placeholder guarding against recursion
+		components[name] = r.structSchema(t, components)
+	}
+	return Schema{Ref: "#/components/schemas/" + name}
+}
+
+// componentName returns the component name of a named struct type: its name
+// without the package paths of type arguments, e.g. PageUser for Page[User].
+// A type whose name is already taken by another component is prefixed with its
+// package name, e.g. billing.User, and numbered if that is taken too.
+func (r *Router) componentName(t reflect.Type) string {
+	rootRouter := r.rootParent()
+	if rootRouter.componentTypes == nil {
+		rootRouter.componentTypes = make(map[string]reflect.Type)
+	}
+
+	base := sanitizeComponentName(t.Name())
+	candidates := []string{base}
+	if pkg := sanitizeComponentName(path.Base(t.PkgPath())); pkg != "" {
+		candidates = append(candidates, pkg+"."+base)
+	}
+	for i := 2; ; i++ {
+		for _, name := range candidates {
+			owner, taken := rootRouter.componentTypes[name]
+			if !taken {
+				// e.g. merged from another router
+				_, taken = rootRouter.openapi.Components.Schemas[name]
+			}
+			if !taken || owner == t {
+				rootRouter.componentTypes[name] = t
+				return name
+			}
+		}
+		candidates = []string{fmt.Sprintf("%s%d", candidates[len(candidates)-1], i)}
+	}
+}
+
+// sanitizeComponentName turns a Go type name into a valid component name,
+// keeping the last element of qualified identifiers and joining them in
+// CamelCase, e.g. Pair[string,github.com/acme/models.User] becomes
+// PairStringUser.
+func sanitizeComponentName(name string) string {
+	var b strings.Builder
+	parts := strings.FieldsFunc(name, func(c rune) bool {
+		return strings.ContainsRune("[]*, ", c)
+	})
+	for i, part := range parts {
+		part = part[strings.LastIndex(part, "/")+1:]
+		part = part[strings.LastIndex(part, ".")+1:]
+		part, _, _ = strings.Cut(part, "·") // numbered types declared in functions
+		part = strings.Map(func(c rune) rune {
+			if c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' {
+				return c
+			}
+			return -1
+		}, part)
+		if i > 0 && part != "" {
+			part = strings.ToUpper(part[:1]) + part[1:]
+		}
+		b.WriteString(part)
+	}
+	return b.String()
+}
+
+// jsonMapKey reports whether encoding/json encodes maps with keys of type t as
+// objects: string and integer keys, or keys implementing encoding.TextMarshaler.
+func jsonMapKey(t reflect.Type) bool {
+	if t.Implements(textMarshalerType) {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	default:
+		return false
+	}
+}
+
+// kindFormat returns the default OpenAPI format of a reflect.Kind, if any.
+func kindFormat(kind reflect.Kind) string {
+	switch kind {
+	case reflect.Int32:
+		return "int32"
+	case reflect.Int64:
+		return "int64"
+	case reflect.Uint8:
+		return "uint8"
+	case reflect.Uint16:
+		return "uint16"
+	case reflect.Uint32:
+		return "uint32"
+	case reflect.Uint64:
+		return "uint64"
+	case reflect.Float32:
+		return "float"
+	case reflect.Float64:
+		return "double"
+	default:
+		return ""
+	}
+}
+
+// kindType maps a reflect.Kind to its OpenAPI type.
+func kindType(kind reflect.Kind) string {
+	switch kind {
+	case reflect.String:
+		return "string"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Struct:
+		return "object"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	default:
+		return "string" // Default to string if unknown
+	}
+}
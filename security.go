@@ -0,0 +1,39 @@
+package router
+
+// UseBearerAuth registers an HTTP bearer security scheme under the given name
+// and requires it for every operation.
+func (r *Router) UseBearerAuth(schemeName string) {
+	r.useSecurityScheme(schemeName, SecurityScheme{
+		Type:   "http",
+		Scheme: "bearer",
+	})
+}
+
+// UseAPIKeyAuth registers a security scheme for an API key sent in the given
+// header under the given name, and requires it for every operation.
+func (r *Router) UseAPIKeyAuth(schemeName string, headerName string) {
+	r.useSecurityScheme(schemeName, SecurityScheme{
+		Type: "apiKey",
+		Name: headerName,
+		In:   "header",
+	})
+}
+
+func (r *Router) useSecurityScheme(name string, scheme SecurityScheme) {
+	rootRouter := r.rootParent()
+	rootRouter.mu.Lock()
+	defer rootRouter.mu.Unlock()
+
+	doc := rootRouter.openapi
+	if doc.Components.SecuritySchemes == nil {
+		doc.Components.SecuritySchemes = make(map[string]SecurityScheme)
+	}
+	doc.Components.SecuritySchemes[name] = scheme
+
+	for _, requirement := range doc.Security {
+		if _, ok := requirement[name]; ok {
+			return
+		}
+	}
+	doc.Security = append(doc.Security, map[string][]string{name: {}})
+}
@@ -0,0 +1,64 @@
+package router
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// GetWithSpec registers a GET route documented by a hand-written OpenAPI
+// operation object in JSON, instead of reflection, e.g.
+//
+//	r.GetWithSpec("/reports/{id}", handler, []byte(`{"summary": "Get report", "responses": {...}}`))
+//
+// Vendor extensions of the fragment are kept. It panics when the fragment is
+// not a valid operation object.
+func (r *Router) GetWithSpec(pattern string, handler http.HandlerFunc, operationJSON []byte) *Route {
+	docs, err := docsFromSpec(operationJSON)
+	if err != nil {
+		panic(fmt.Sprintf("router: invalid operation spec for GET %s: %v", pattern, err))
+	}
+	return r.handle(http.MethodGet, pattern, handler, docs)
+}
+
+// docsFromSpec parses an OpenAPI operation object into Docs.
+func docsFromSpec(operationJSON []byte) (Docs, error) {
+	var op Operation
+	if err := json.Unmarshal(operationJSON, &op); err != nil {
+		return Docs{}, err
+	}
+
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(operationJSON, &members); err != nil {
+		return Docs{}, err
+	}
+	for key, raw := range members {
+		if !strings.HasPrefix(key, "x-") {
+			continue
+		}
+
+		var value any
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return Docs{}, err
+		}
+		if op.Extensions == nil {
+			op.Extensions = make(map[string]any)
+		}
+		op.Extensions[key] = value
+	}
+
+	return Docs{
+		Tags:        op.Tags,
+		Summary:     op.Summary,
+		Description: op.Description,
+		OperationID: op.OperationID,
+		Parameters:  op.Parameters,
+		RequestBody: op.RequestBody,
+		Responses:   op.Responses,
+		Security:    op.Security,
+		Servers:     op.Servers,
+		Extensions:  op.Extensions,
+		Deprecated:  op.Deprecated,
+	}, nil
+}
@@ -0,0 +1,83 @@
+package router
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// nameRoute records the pattern of a named route.
+func (r *Router) nameRoute(name, pattern string) {
+	rootRouter := r.rootParent()
+	rootRouter.mu.Lock()
+	defer rootRouter.mu.Unlock()
+
+	if existing, ok := rootRouter.namedRoutes[name]; ok && existing != pattern {
+		panic(fmt.Sprintf("router: route name %q is used by %s and %s", name, existing, pattern))
+	}
+
+	if rootRouter.namedRoutes == nil {
+		rootRouter.namedRoutes = make(map[string]string)
+	}
+	rootRouter.namedRoutes[name] = pattern
+}
+
+// URLFor builds the path of the route registered with the given Docs.Name,
+// filling its wildcards from the key value pairs, e.g.
+//
+//	r.URLFor("user.show", "id", "123") // /users/123
+//
+// Values are path escaped, except for the slashes of remaining wildcards such
+// as {path...}. It returns an error when the name is unknown or a wildcard has
+// no value.
+func (r *Router) URLFor(name string, pairs ...string) (string, error) {
+	if len(pairs)%2 != 0 {
+		return "", fmt.Errorf("router: URLFor %q: odd number of key value arguments", name)
+	}
+
+	rootRouter := r.rootParent()
+	rootRouter.mu.RLock()
+	pattern, ok := rootRouter.namedRoutes[name]
+	rootRouter.mu.RUnlock()
+
+	if !ok {
+		return "", fmt.Errorf("router: URLFor: unknown route name %q", name)
+	}
+
+	values := make(map[string]string, len(pairs)/2)
+	for i := 0; i < len(pairs); i += 2 {
+		values[pairs[i]] = pairs[i+1]
+	}
+
+	segments := strings.Split(pattern, "/")
+	for i, segment := range segments {
+		if !strings.HasPrefix(segment, "{") || !strings.HasSuffix(segment, "}") {
+			continue
+		}
+
+		param := segment[1 : len(segment)-1]
+		if param == "$" {
+			segments[i] = ""
+			continue
+		}
+
+		param, remaining := strings.CutSuffix(param, "...")
+		value, ok := values[param]
+		if !ok {
+			return "", fmt.Errorf("router: URLFor %q: missing value for {%s}", name, param)
+		}
+
+		if !remaining {
+			segments[i] = url.PathEscape(value)
+			continue
+		}
+
+		parts := strings.Split(value, "/")
+		for j, part := range parts {
+			parts[j] = url.PathEscape(part)
+		}
+		segments[i] = strings.Join(parts, "/")
+	}
+
+	return strings.Join(segments, "/"), nil
+}
@@ -0,0 +1,121 @@
+package router
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ValidationError describes a single structural violation in an OpenAPI document.
+type ValidationError struct {
+	Path    string // JSON path of the offending field, e.g. $.paths["/users"].get.responses
+	Message string // Human readable description of the violation
+}
+
+func (e ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Path, e.Message)
+}
+
+// ValidationErrors is the list of violations returned by Validate.
+type ValidationErrors []ValidationError
+
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Validate checks the document against the structural rules declared in the
+// `validate` struct tags of the OpenAPI types. Supported rules are `required`
+// (the field must not be empty) and `min=N` (maps and slices must hold at least
+// N entries). It returns ValidationErrors, or nil when the document is valid.
+func (o *OpenAPI) Validate() error {
+	var errs ValidationErrors
+	validateValue(reflect.ValueOf(o), "$", &errs)
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+func validateValue(v reflect.Value, path string, errs *ValidationErrors) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return
+		}
+		validateValue(v.Elem(), path, errs)
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < v.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+
+			name := field.Name
+			if jsonTag := field.Tag.Get("json"); jsonTag != "" {
+				if jsonTag == "-" {
+					continue
+				}
+				if n := strings.Split(jsonTag, ",")[0]; n != "" {
+					name = n
+				}
+			}
+
+			fieldPath := path + "." + name
+			fieldValue := v.Field(i)
+			if validateRules(fieldValue, fieldPath, field.Tag.Get("validate"), errs) {
+				validateValue(fieldValue, fieldPath, errs)
+			}
+		}
+	case reflect.Map:
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+		})
+		for _, k := range keys {
+			validateValue(v.MapIndex(k), fmt.Sprintf("%s[%q]", path, fmt.Sprint(k.Interface())), errs)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			validateValue(v.Index(i), fmt.Sprintf("%s[%d]", path, i), errs)
+		}
+	}
+}
+
+// validateRules applies the rules of a single `validate` tag and reports whether
+// the value is present, and thus worth descending into.
+func validateRules(v reflect.Value, path string, tag string, errs *ValidationErrors) bool {
+	if tag == "" {
+		return true
+	}
+
+	for _, rule := range strings.Split(tag, ",") {
+		name, arg, _ := strings.Cut(rule, "=")
+		switch name {
+		case "required":
+			if v.IsZero() {
+				*errs = append(*errs, ValidationError{Path: path, Message: "field is required"})
+				return false
+			}
+		case "min":
+			n, err := strconv.Atoi(arg)
+			if err != nil {
+				continue
+			}
+			switch v.Kind() {
+			case reflect.Map, reflect.Slice, reflect.Array, reflect.String:
+				if v.Len() < n {
+					*errs = append(*errs, ValidationError{Path: path, Message: fmt.Sprintf("must contain at least %d item(s)", n)})
+				}
+			}
+		}
+	}
+
+	return true
+}
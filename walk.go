@@ -0,0 +1,95 @@
+package router
+
+// mapSchemaChildren returns a copy of the schema whose nested schemas are
+// replaced by the result of fn.
+func mapSchemaChildren(s Schema, fn func(*Schema) *Schema) Schema {
+	s.Items = fn(s.Items)
+	s.AdditionalProperties = fn(s.AdditionalProperties)
+	if s.Properties != nil {
+		props := make(map[string]Schema, len(s.Properties))
+		for name, prop := range s.Properties {
+			if p := fn(&prop); p != nil {
+				props[name] = *p
+			}
+		}
+		s.Properties = props
+	}
+	if s.AllOf != nil {
+		allOf := make([]Schema, 0, len(s.AllOf))
+		for _, a := range s.AllOf {
+			if c := fn(&a); c != nil {
+				allOf = append(allOf, *c)
+			}
+		}
+		s.AllOf = allOf
+	}
+	if s.OneOf != nil {
+		oneOf := make([]Schema, 0, len(s.OneOf))
+		for _, o := range s.OneOf {
+			if c := fn(&o); c != nil {
+				oneOf = append(oneOf, *c)
+			}
+		}
+		s.OneOf = oneOf
+	}
+
+	return s
+}
+
+// mapOperationSchemas returns a copy of the operation whose parameter, request
+// body and response schemas are replaced by the result of fn.
+func mapOperationSchemas(op *Operation, fn func(*Schema) *Schema) *Operation {
+	if op == nil {
+		return nil
+	}
+
+	c := *op
+	if op.Parameters != nil {
+		c.Parameters = make([]Parameter, len(op.Parameters))
+		for i, p := range op.Parameters {
+			p.Schema = fn(p.Schema)
+			c.Parameters[i] = p
+		}
+	}
+
+	if op.RequestBody != nil {
+		rb := *op.RequestBody
+		rb.Content = mapContentSchemas(rb.Content, fn)
+		c.RequestBody = &rb
+	}
+
+	if op.Responses != nil {
+		c.Responses = make(map[string]Response, len(op.Responses))
+		for code, res := range op.Responses {
+			res.Content = mapContentSchemas(res.Content, fn)
+			c.Responses[code] = res
+		}
+	}
+
+	return &c
+}
+
+func mapContentSchemas(content map[string]MediaType, fn func(*Schema) *Schema) map[string]MediaType {
+	if content == nil {
+		return nil
+	}
+
+	c := make(map[string]MediaType, len(content))
+	for ct, mt := range content {
+		mt.Schema = fn(mt.Schema)
+		c[ct] = mt
+	}
+	return c
+}
+
+// mapPathItemOperations returns a copy of the path item with every operation
+// replaced by the result of fn.
+func mapPathItemOperations(item PathItem, fn func(*Operation) *Operation) PathItem {
+	return PathItem{
+		Get:    fn(item.Get),
+		Post:   fn(item.Post),
+		Put:    fn(item.Put),
+		Delete: fn(item.Delete),
+		Patch:  fn(item.Patch),
+	}
+}
@@ -0,0 +1,178 @@
+package router
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// OpenAPIYAML returns the OpenAPI document serialized as YAML. The document
+// is first encoded as JSON, so keys and omitted fields match the JSON output
+// exactly; strings are emitted double quoted to keep their value unambiguous.
+func (r *Router) OpenAPIYAML() ([]byte, error) {
+	var doc bytes.Buffer
+	enc := json.NewEncoder(&doc)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(r.OpenAPI()); err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(&doc)
+	dec.UseNumber()
+
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	if tok != json.Delim('{') {
+		return nil, fmt.Errorf("router: unexpected OpenAPI token %v", tok)
+	}
+
+	var out bytes.Buffer
+	if err := writeYAMLObject(&out, dec, 0, ""); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// writeYAMLObject writes the members of the object opened by the last token
+// as a block mapping. The first key is preceded by first instead of the
+// indentation, which lets sequence items start on the dash line.
+func writeYAMLObject(out *bytes.Buffer, dec *json.Decoder, indent int, first string) error {
+	for i := 0; dec.More(); i++ {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+
+		if i == 0 && first != "" {
+			out.WriteString(first)
+		} else {
+			out.WriteString(strings.Repeat(" ", indent))
+		}
+		out.WriteString(yamlKey(tok.(string)))
+		out.WriteByte(':')
+
+		if err := writeYAMLValue(out, dec, indent); err != nil {
+			return err
+		}
+	}
+
+	_, err := dec.Token() // closing }
+	return err
+}
+
+// writeYAMLValue writes the next value of the decoder, following a key or a
+// dash written by the caller at the given indentation.
+func writeYAMLValue(out *bytes.Buffer, dec *json.Decoder, indent int) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	switch tok {
+	case json.Delim('{'):
+		if !dec.More() {
+			out.WriteString(" {}\n")
+			_, err = dec.Token()
+			return err
+		}
+		out.WriteByte('\n')
+		return writeYAMLObject(out, dec, indent+2, "")
+	case json.Delim('['):
+		if !dec.More() {
+			out.WriteString(" []\n")
+			_, err = dec.Token()
+			return err
+		}
+		out.WriteByte('\n')
+		return writeYAMLArray(out, dec, indent+2)
+	}
+
+	out.WriteByte(' ')
+	out.WriteString(yamlScalar(tok))
+	out.WriteByte('\n')
+	return nil
+}
+
+// writeYAMLArray writes the items of the array opened by the last token as a
+// block sequence.
+func writeYAMLArray(out *bytes.Buffer, dec *json.Decoder, indent int) error {
+	dash := strings.Repeat(" ", indent) + "- "
+
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+
+		switch tok {
+		case json.Delim('{'):
+			if !dec.More() {
+				out.WriteString(dash + "{}\n")
+				_, err = dec.Token()
+			} else {
+				err = writeYAMLObject(out, dec, indent+2, dash)
+			}
+		case json.Delim('['):
+			if !dec.More() {
+				out.WriteString(dash + "[]\n")
+				_, err = dec.Token()
+			} else {
+				out.WriteString(dash[:len(dash)-1] + "\n")
+				err = writeYAMLArray(out, dec, indent+2)
+			}
+		default:
+			out.WriteString(dash + yamlScalar(tok) + "\n")
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err := dec.Token() // closing ]
+	return err
+}
+
+var yamlPlainKey = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_.$-]*$`)
+
+// yamlKey returns the key unquoted when YAML reads it back as the same string.
+func yamlKey(key string) string {
+	switch strings.ToLower(key) {
+	case "true", "false", "null", "yes", "no", "on", "off", "y", "n":
+		return yamlString(key)
+	}
+	if yamlPlainKey.MatchString(key) {
+		return key
+	}
+	return yamlString(key)
+}
+
+// yamlScalar returns the YAML representation of a JSON scalar token.
+func yamlScalar(tok json.Token) string {
+	switch v := tok.(type) {
+	case string:
+		return yamlString(v)
+	case json.Number:
+		return v.String()
+	case bool:
+		if v {
+			return "true"
+		}
+		return "false"
+	default:
+		return "null"
+	}
+}
+
+// yamlString double quotes a string. JSON escapes are a subset of the YAML
+// double quoted escapes.
+func yamlString(s string) string {
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(s)
+	return strings.TrimSuffix(b.String(), "\n")
+}